@@ -0,0 +1,107 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// TypeResized indicates whether the last flavor change of a VirtualMachine
+// has completed.
+const TypeResized xpv1.ConditionType = "Resized"
+
+// Reasons a VirtualMachine is or is not resized.
+const (
+	ReasonResizing        xpv1.ConditionReason = "Resizing"
+	ReasonResizeConfirmed xpv1.ConditionReason = "Confirmed"
+	ReasonResizeReverted  xpv1.ConditionReason = "Reverted"
+)
+
+// Resizing returns a condition that indicates the VirtualMachine is changing
+// from one flavor to another.
+func Resizing(from, to string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeResized,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonResizing,
+		Message:            fmt.Sprintf("resizing from flavor %s to %s", from, to),
+	}
+}
+
+// ResizeConfirmed returns a condition that indicates the VirtualMachine was
+// resized to the supplied flavor.
+func ResizeConfirmed(to string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeResized,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonResizeConfirmed,
+		Message:            fmt.Sprintf("resized to flavor %s", to),
+	}
+}
+
+// ResizeReverted returns a condition that indicates the resize of the
+// VirtualMachine failed and it was returned to its original flavor.
+func ResizeReverted(from, to string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeResized,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonResizeReverted,
+		Message:            fmt.Sprintf("resize to flavor %s failed and was reverted to %s; change spec.forProvider.flavorRef to retry", to, from),
+	}
+}
+
+// TypeUserDataRendered indicates whether the user data template of a
+// VirtualMachine could be rendered.
+const TypeUserDataRendered xpv1.ConditionType = "UserDataRendered"
+
+// Reasons the user data template of a VirtualMachine is or is not rendered.
+const (
+	ReasonRendered     xpv1.ConditionReason = "Rendered"
+	ReasonRenderFailed xpv1.ConditionReason = "RenderFailed"
+)
+
+// UserDataRendered returns a condition that indicates the user data template
+// of the VirtualMachine was rendered.
+func UserDataRendered() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeUserDataRendered,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRendered,
+	}
+}
+
+// UserDataRenderFailed returns a condition that indicates the user data
+// template of the VirtualMachine could not be rendered. The VirtualMachine is
+// not created until the template or its variables are fixed.
+func UserDataRenderFailed(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeUserDataRendered,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRenderFailed,
+		Message:            err.Error(),
+	}
+}
@@ -34,15 +34,41 @@ type FloatingipParameters struct {
 	FloatingNetworkId string `json:"floatingNetworkId,omitempty"`
 	QosPolicyId       string `json:"qosPolicyId,omitempty"`
 	RouteId           string `json:"routeId,omitempty"`
-	Description       string `json:"description,omitempty"`
-	ReservationId     string `json:"reservationId,omitempty"`
-	Bandwidth         int64  `json:"bandwidth,omitempty"`
-	AvailabilityZone  string `json:"availabilityZone,omitempty"`
+
+	// Description of the floating IP. An empty description clears the
+	// description of the floating IP, while an unset one leaves it unchanged.
+	// +optional
+	Description *string `json:"description,omitempty"`
+
+	ReservationId    string `json:"reservationId,omitempty"`
+	Bandwidth        int64  `json:"bandwidth,omitempty"`
+	AvailabilityZone string `json:"availabilityZone,omitempty"`
 }
 
 // FloatingipObservation are the observable fields of a Floatingip.
 type FloatingipObservation struct {
-	Status string `json:"status,omitempty"`
+	ID                string `json:"id,omitempty"`
+	Name              string `json:"name,omitempty"`
+	Description       string `json:"description,omitempty"`
+	ProjectId         string `json:"projectId,omitempty"`
+	CellId            string `json:"cellId,omitempty"`
+	Isp               string `json:"isp,omitempty"`
+	FloatingNetworkId string `json:"floatingNetworkId,omitempty"`
+	QosPolicyId       string `json:"qosPolicyId,omitempty"`
+	RouteId           string `json:"routeId,omitempty"`
+	Bandwidth         int64  `json:"bandwidth,omitempty"`
+	Status            string `json:"status,omitempty"`
+
+	// FloatingIPAddress is the public IP address allocated to the
+	// Floatingip.
+	FloatingIPAddress string `json:"floatingIpAddress,omitempty"`
+
+	// FixedIPAddress is the private IP address the Floatingip is associated
+	// with, if any.
+	FixedIPAddress string       `json:"fixedIpAddress,omitempty"`
+	UserId         string       `json:"userId,omitempty"`
+	Created        *metav1.Time `json:"created,omitempty"`
+	Updated        *metav1.Time `json:"updated,omitempty"`
 }
 
 // A FloatingipSpec defines the desired state of a Floatingip.
@@ -63,6 +89,10 @@ type FloatingipStatus struct {
 // +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
 // +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.atProvider.status"
+// +kubebuilder:printcolumn:name="ADDRESS",type="string",JSONPath=".status.atProvider.floatingIpAddress"
+// +kubebuilder:printcolumn:name="FIXED-IP",type="string",JSONPath=".status.atProvider.fixedIpAddress",priority=1
+// +kubebuilder:printcolumn:name="BANDWIDTH",type="integer",JSONPath=".status.atProvider.bandwidth",priority=1
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,ucan},shortName=fipu
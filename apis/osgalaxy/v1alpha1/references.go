@@ -0,0 +1,36 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reference"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// ReadyExternalName returns an extractor of the external name of a managed
+// resource that is Ready. Resolving a reference to a resource that is not yet
+// Ready, or to no resource at all, fails, and is retried until it is.
+func ReadyExternalName() reference.ExtractValueFn {
+	return func(mg resource.Managed) string {
+		if mg == nil || !resource.IsConditionTrue(mg.GetCondition(xpv1.TypeReady)) {
+			return ""
+		}
+		return meta.GetExternalName(mg)
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+func TestReadyExternalName(t *testing.T) {
+	volume := func(c ...xpv1.Condition) *Volume {
+		v := &Volume{}
+		meta.SetExternalName(v, "vol-1")
+		v.SetConditions(c...)
+		return v
+	}
+
+	cases := map[string]struct {
+		reason string
+		mg     resource.Managed
+		want   string
+	}{
+		"Ready": {
+			reason: "The external name of a Ready resource should be extracted.",
+			mg:     volume(xpv1.Available()),
+			want:   "vol-1",
+		},
+		"NotReady": {
+			reason: "The external name of a resource that is not Ready should not be extracted.",
+			mg:     volume(xpv1.Creating()),
+		},
+		"NoConditions": {
+			reason: "The external name of a resource that was never observed should not be extracted.",
+			mg:     volume(),
+		},
+		"NotManaged": {
+			reason: "Nothing should be extracted from a resource that is not a managed resource.",
+			mg:     nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := ReadyExternalName()(tc.mg); got != tc.want {
+				t.Errorf("\n%s\nReadyExternalName()(...): want %q, got %q", tc.reason, tc.want, got)
+			}
+		})
+	}
+}
@@ -26,8 +26,30 @@ import (
 )
 
 type PersonalityParameters struct {
-	Contents string `json:"contents"`
-	Path     string `json:"path"`
+	// Contents is the base64-encoded content of the file.
+	// +optional
+	Contents string `json:"contents,omitempty"`
+
+	// ContentsSecretRef selects a key of a Secret that holds the content of
+	// the file, which is encoded by the controller. It takes precedence over
+	// Contents.
+	// +optional
+	ContentsSecretRef *xpv1.SecretKeySelector `json:"contentsSecretRef,omitempty"`
+
+	Path string `json:"path"`
+}
+
+// A ConfigMapKeySelector is a reference to a key of a ConfigMap in an
+// arbitrary namespace.
+type ConfigMapKeySelector struct {
+	// Name of the ConfigMap.
+	Name string `json:"name"`
+
+	// Namespace of the ConfigMap.
+	Namespace string `json:"namespace"`
+
+	// The key to select.
+	Key string `json:"key"`
 }
 
 type SecurityGroupParameters struct {
@@ -42,12 +64,80 @@ type BlockDeviceParameters struct {
 	DestinationType     string `json:"destinationType,omitempty"`
 	VolumeSize          int64  `json:"volumeSize,omitempty"`
 	VolumeType          string `json:"volumeType,omitempty"`
-	UUID                string `json:"uuid,omitempty"`
+
+	// UUID is the ID of the source of the block device, e.g. a UCAN volume.
+	// +crossplane:generate:reference:type=Volume
+	// +crossplane:generate:reference:extractor=ReadyExternalName()
+	// +crossplane:generate:reference:refFieldName=VolumeRef
+	// +crossplane:generate:reference:selectorFieldName=VolumeSelector
+	UUID string `json:"uuid,omitempty"`
+
+	// VolumeRef references a Volume to retrieve its UUID. The Volume must
+	// be Ready before it is resolved.
+	// +optional
+	VolumeRef *xpv1.Reference `json:"volumeRef,omitempty"`
+
+	// VolumeSelector selects a Volume to retrieve its UUID. The Volume must
+	// be Ready before it is resolved.
+	// +optional
+	VolumeSelector *xpv1.Selector `json:"volumeSelector,omitempty"`
+}
+
+// A UserDataTemplate renders the user data of a VirtualMachine with Go's
+// text/template package.
+type UserDataTemplate struct {
+	// Template is rendered with the name, labels and annotations of the
+	// VirtualMachine as .Name, .Labels and .Annotations, and with its
+	// variables as .Values, e.g. {{ .Values.address }}. Annotations with the
+	// prefix crossplane.io/ or ucan.io/ are left out of .Annotations.
+	// Referring to a label, annotation or variable that is not set is an
+	// error.
+	Template string `json:"template"`
+
+	// Variables are resolved before the template is rendered.
+	// +optional
+	Variables []TemplateVariable `json:"variables,omitempty"`
+}
+
+// A TemplateVariable is a value of a UserDataTemplate. Exactly one of its
+// sources must be set.
+type TemplateVariable struct {
+	// Name of the variable in .Values.
+	Name string `json:"name"`
+
+	// SecretKeyRef selects a key of a Secret.
+	// +optional
+	SecretKeyRef *xpv1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+
+	// ConfigMapKeyRef selects a key of a ConfigMap.
+	// +optional
+	ConfigMapKeyRef *ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+
+	// ConnectionDetailRef selects a connection detail of another managed
+	// resource, e.g. the address of a Floatingip.
+	// +optional
+	ConnectionDetailRef *ConnectionDetailSelector `json:"connectionDetailRef,omitempty"`
+}
+
+// A ConnectionDetailSelector selects a connection detail of a managed resource
+// of this API group. The resource must write its connection details to a
+// Secret with spec.writeConnectionSecretToRef.
+type ConnectionDetailSelector struct {
+	// Kind of the managed resource.
+	// +kubebuilder:validation:Enum=VirtualMachine;Volume;Floatingip
+	Kind string `json:"kind"`
+
+	// Name of the managed resource.
+	Name string `json:"name"`
+
+	// The connection detail to select, e.g. address.
+	Key string `json:"key"`
 }
 
 // VirtualMachineParameters are the configurable fields of a VirtualMachine.
 type VirtualMachineParameters struct {
 	Name               string                    `json:"name,omitempty"`
+	Description        string                    `json:"description,omitempty"`
 	ProjectId          string                    `json:"projectId,omitempty"`
 	CellId             string                    `json:"cellId,omitempty"`
 	AccessIPV4         string                    `json:"accessIpV4,omitempty"`
@@ -60,11 +150,135 @@ type VirtualMachineParameters struct {
 	Metadata           map[string]string         `json:"metadata,omitempty"`
 	Personality        []PersonalityParameters   `json:"personality,omitempty"`
 	SecurityGroups     []SecurityGroupParameters `json:"securityGroups,omitempty"`
+
+	// UserDataSecretRef selects a key of a Secret that holds the user data.
+	// Unlike UserData, which must be base64-encoded, the referenced user data
+	// is encoded by the controller. It takes precedence over UserData and
+	// UserDataConfigMapRef.
+	// +optional
+	UserDataSecretRef *xpv1.SecretKeySelector `json:"userDataSecretRef,omitempty"`
+
+	// UserDataConfigMapRef selects a key of a ConfigMap that holds the user
+	// data. It takes precedence over UserData.
+	// +optional
+	UserDataConfigMapRef *ConfigMapKeySelector `json:"userDataConfigMapRef,omitempty"`
+
+	// UserDataTemplate renders the user data from a template. It takes
+	// precedence over UserData, UserDataSecretRef and UserDataConfigMapRef.
+	// +optional
+	UserDataTemplate *UserDataTemplate `json:"userDataTemplate,omitempty"`
+
+	// PowerState is the desired power state of the VirtualMachine. The power
+	// state is not managed if it is omitted.
+	// +optional
+	// +kubebuilder:validation:Enum=Running;Stopped;Paused;Suspended
+	PowerState string `json:"powerState,omitempty"`
 }
 
+// Power states of a VirtualMachine.
+const (
+	PowerStateRunning   = "Running"
+	PowerStateStopped   = "Stopped"
+	PowerStatePaused    = "Paused"
+	PowerStateSuspended = "Suspended"
+)
+
 // VirtualMachineObservation are the observable fields of a VirtualMachine.
 type VirtualMachineObservation struct {
-	Status string `json:"status,omitempty"`
+	ID               string            `json:"id,omitempty"`
+	Name             string            `json:"name,omitempty"`
+	Description      string            `json:"description,omitempty"`
+	ProjectId        string            `json:"projectId,omitempty"`
+	UserId           string            `json:"userId,omitempty"`
+	AccessIPV4       string            `json:"accessIpV4,omitempty"`
+	AccessIPV6       string            `json:"accessIpV6,omitempty"`
+	ImageRef         string            `json:"imageRef,omitempty"`
+	FlavorRef        string            `json:"flavorRef,omitempty"`
+	AvailabilityZone string            `json:"availabilityZone,omitempty"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+	SecurityGroups   []string          `json:"securityGroups,omitempty"`
+	Tags             []string          `json:"tags,omitempty"`
+	Status           string            `json:"status,omitempty"`
+	HostID           string            `json:"hostId,omitempty"`
+	KeyName          string            `json:"keyName,omitempty"`
+	Locked           bool              `json:"locked,omitempty"`
+	LockedReason     string            `json:"lockedReason,omitempty"`
+	Progress         int64             `json:"progress,omitempty"`
+	Created          *metav1.Time      `json:"created,omitempty"`
+	Updated          *metav1.Time      `json:"updated,omitempty"`
+
+	// PrivateIPV4 and PublicIPV4 are the first fixed and floating IPv4
+	// addresses of the VirtualMachine.
+	PrivateIPV4 string `json:"privateIpV4,omitempty"`
+	PublicIPV4  string `json:"publicIpV4,omitempty"`
+
+	Addresses       []AddressObservation        `json:"addresses,omitempty"`
+	Flavor          *FlavorObservation          `json:"flavor,omitempty"`
+	VolumesAttached []AttachedVolumeObservation `json:"volumesAttached,omitempty"`
+
+	// UserDataHash and PersonalityHash are the SHA-256 hashes of the sources
+	// of the user data and personality files the VirtualMachine was created
+	// with, as recorded in its annotations. Contents read from a Secret or
+	// ConfigMap are hashed from its UID and resource version, not its data.
+	UserDataHash    string `json:"userDataHash,omitempty"`
+	PersonalityHash string `json:"personalityHash,omitempty"`
+
+	// PowerState is the observed power state of the VirtualMachine. It is
+	// empty while the server is transitioning between states.
+	PowerState string `json:"powerState,omitempty"`
+
+	// Resize reports the progress of the last flavor change.
+	Resize *ResizeObservation `json:"resize,omitempty"`
+
+	// MetadataKeys are the keys of spec.forProvider.metadata last applied to
+	// the server. A key removed from spec.forProvider.metadata is deleted
+	// from the server metadata.
+	MetadataKeys []string `json:"metadataKeys,omitempty"`
+}
+
+// An AddressObservation is an IP address of a VirtualMachine.
+type AddressObservation struct {
+	// Network is the name of the network the address belongs to.
+	Network string `json:"network,omitempty"`
+	Addr    string `json:"addr,omitempty"`
+	Version int64  `json:"version,omitempty"`
+	// Type is fixed or floating.
+	Type    string `json:"type,omitempty"`
+	MACAddr string `json:"macAddr,omitempty"`
+}
+
+// A FlavorObservation describes the flavor of a VirtualMachine.
+type FlavorObservation struct {
+	ID        string `json:"id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	VCPUs     int64  `json:"vcpus,omitempty"`
+	RAM       int64  `json:"ram,omitempty"`
+	Disk      int64  `json:"disk,omitempty"`
+	Swap      int64  `json:"swap,omitempty"`
+	Ephemeral int64  `json:"ephemeral,omitempty"`
+}
+
+// An AttachedVolumeObservation is a volume attached to a VirtualMachine.
+type AttachedVolumeObservation struct {
+	ID                  string `json:"id,omitempty"`
+	DeleteOnTermination bool   `json:"deleteOnTermination,omitempty"`
+}
+
+// Phases of a flavor change.
+const (
+	ResizePhaseResizing   = "Resizing"
+	ResizePhaseConfirming = "Confirming"
+	ResizePhaseConfirmed  = "Confirmed"
+	ResizePhaseReverting  = "Reverting"
+	ResizePhaseReverted   = "Reverted"
+)
+
+// A ResizeObservation reports the progress of a flavor change.
+type ResizeObservation struct {
+	FromFlavorRef string `json:"fromFlavorRef,omitempty"`
+	ToFlavorRef   string `json:"toFlavorRef,omitempty"`
+	Phase         string `json:"phase,omitempty"`
+	Progress      int64  `json:"progress,omitempty"`
 }
 
 // A VirtualMachineSpec defines the desired state of a VirtualMachine.
@@ -85,6 +299,10 @@ type VirtualMachineStatus struct {
 // +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
 // +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.atProvider.status"
+// +kubebuilder:printcolumn:name="IP",type="string",JSONPath=".status.atProvider.privateIpV4"
+// +kubebuilder:printcolumn:name="PUBLIC-IP",type="string",JSONPath=".status.atProvider.publicIpV4",priority=1
+// +kubebuilder:printcolumn:name="FLAVOR",type="string",JSONPath=".status.atProvider.flavor.name",priority=1
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,ucan},shortName=vmu
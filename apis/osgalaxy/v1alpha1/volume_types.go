@@ -41,11 +41,75 @@ type VolumeParameters struct {
 	AvailabilityZone string                     `json:"availabilityZone,omitempty"`
 	ImageRef         string                     `json:"imageRef,omitempty"`
 	SchedulerHints   []SchedulerHintsParameters `json:"schedulerHints,omitempty"`
+
+	// MigrationPolicy controls whether the data of the volume may be migrated
+	// to another backend when volumeType changes. A volume that cannot be
+	// retyped in place fails to retype with the default policy of never.
+	// +optional
+	// +kubebuilder:validation:Enum=never;on-demand
+	MigrationPolicy string `json:"migrationPolicy,omitempty"`
 }
 
+// Migration policies of a Volume.
+const (
+	MigrationPolicyNever    = "never"
+	MigrationPolicyOnDemand = "on-demand"
+)
+
 // VolumeObservation are the observable fields of a Volume.
 type VolumeObservation struct {
-	Status string `json:"status,omitempty"`
+	ID               string `json:"id,omitempty"`
+	Name             string `json:"name,omitempty"`
+	Description      string `json:"description,omitempty"`
+	ProjectId        string `json:"projectId,omitempty"`
+	UserId           string `json:"userId,omitempty"`
+	VolumeType       string `json:"volumeType,omitempty"`
+	Size             int64  `json:"size,omitempty"`
+	Multiattach      bool   `json:"multiattach,omitempty"`
+	AvailabilityZone string `json:"availabilityZone,omitempty"`
+	Status           string `json:"status,omitempty"`
+
+	// MigrationStatus is the status of the last migration of the volume's
+	// data, e.g. migrating, success or error.
+	MigrationStatus string `json:"migrationStatus,omitempty"`
+
+	Bootable          bool         `json:"bootable,omitempty"`
+	Encrypted         bool         `json:"encrypted,omitempty"`
+	Host              string       `json:"host,omitempty"`
+	ReplicationStatus string       `json:"replicationStatus,omitempty"`
+	SourceVolumeID    string       `json:"sourceVolumeId,omitempty"`
+	SnapshotID        string       `json:"snapshotId,omitempty"`
+	Created           *metav1.Time `json:"created,omitempty"`
+	Updated           *metav1.Time `json:"updated,omitempty"`
+
+	Attachments []AttachmentObservation `json:"attachments,omitempty"`
+
+	// Retype reports the progress of the last volume type change.
+	Retype *RetypeObservation `json:"retype,omitempty"`
+}
+
+// Phases of a volume type change.
+const (
+	RetypePhaseRetyping = "Retyping"
+	RetypePhaseRetyped  = "Retyped"
+	RetypePhaseFailed   = "Failed"
+)
+
+// A RetypeObservation reports the progress of a volume type change.
+type RetypeObservation struct {
+	FromVolumeType string `json:"fromVolumeType,omitempty"`
+	ToVolumeType   string `json:"toVolumeType,omitempty"`
+	Phase          string `json:"phase,omitempty"`
+}
+
+// An AttachmentObservation is an attachment of a Volume to a server.
+type AttachmentObservation struct {
+	ID           string       `json:"id,omitempty"`
+	ServerID     string       `json:"serverId,omitempty"`
+	Device       string       `json:"device,omitempty"`
+	HostName     string       `json:"hostName,omitempty"`
+	AttachedAt   *metav1.Time `json:"attachedAt,omitempty"`
+	AttachmentID string       `json:"attachmentId,omitempty"`
 }
 
 // A VolumeSpec defines the desired state of a Volume.
@@ -66,6 +130,10 @@ type VolumeStatus struct {
 // +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
 // +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.atProvider.status"
+// +kubebuilder:printcolumn:name="SIZE",type="integer",JSONPath=".status.atProvider.size"
+// +kubebuilder:printcolumn:name="TYPE",type="string",JSONPath=".status.atProvider.volumeType",priority=1
+// +kubebuilder:printcolumn:name="ATTACHED-TO",type="string",JSONPath=".status.atProvider.attachments[0].serverId",priority=1
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,ucan},shortName=volu
@@ -0,0 +1,134 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// VolumeAttachmentParameters are the configurable fields of a
+// VolumeAttachment. Changing serverId or volumeId detaches the volume and
+// attaches the desired one once it is available again. A reference is only
+// resolved while the ID it sets is empty, so changing serverRef or volumeRef
+// moves the attachment only if the reference has policy.resolve Always.
+type VolumeAttachmentParameters struct {
+	// ServerID is the ID of the UCAN server to attach the volume to.
+	// +crossplane:generate:reference:type=VirtualMachine
+	// +crossplane:generate:reference:extractor=ReadyExternalName()
+	// +crossplane:generate:reference:refFieldName=ServerRef
+	// +crossplane:generate:reference:selectorFieldName=ServerSelector
+	// +optional
+	ServerID string `json:"serverId,omitempty"`
+
+	// ServerRef references a VirtualMachine to retrieve its ServerID.
+	// +optional
+	ServerRef *xpv1.Reference `json:"serverRef,omitempty"`
+
+	// ServerSelector selects a VirtualMachine to retrieve its ServerID.
+	// +optional
+	ServerSelector *xpv1.Selector `json:"serverSelector,omitempty"`
+
+	// VolumeID is the ID of the UCAN volume to attach.
+	// +crossplane:generate:reference:type=Volume
+	// +crossplane:generate:reference:extractor=ReadyExternalName()
+	// +crossplane:generate:reference:refFieldName=VolumeRef
+	// +crossplane:generate:reference:selectorFieldName=VolumeSelector
+	// +optional
+	VolumeID string `json:"volumeId,omitempty"`
+
+	// VolumeRef references a Volume to retrieve its VolumeID.
+	// +optional
+	VolumeRef *xpv1.Reference `json:"volumeRef,omitempty"`
+
+	// VolumeSelector selects a Volume to retrieve its VolumeID.
+	// +optional
+	VolumeSelector *xpv1.Selector `json:"volumeSelector,omitempty"`
+
+	// Device is the requested device path of the volume, e.g. /dev/vdb.
+	// UCAN chooses the device path if it is omitted, and may not honor it
+	// otherwise, so it is not compared to the observed device path.
+	// +optional
+	Device string `json:"device,omitempty"`
+}
+
+// VolumeAttachmentObservation are the observable fields of a
+// VolumeAttachment.
+type VolumeAttachmentObservation struct {
+	ID       string `json:"id,omitempty"`
+	ServerID string `json:"serverId,omitempty"`
+	VolumeID string `json:"volumeId,omitempty"`
+
+	// Device is the device path of the volume on the server.
+	Device string `json:"device,omitempty"`
+}
+
+// A VolumeAttachmentSpec defines the desired state of a VolumeAttachment.
+type VolumeAttachmentSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       VolumeAttachmentParameters `json:"forProvider"`
+}
+
+// A VolumeAttachmentStatus represents the observed state of a
+// VolumeAttachment.
+type VolumeAttachmentStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          VolumeAttachmentObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A VolumeAttachment attaches a Volume to a VirtualMachine.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="DEVICE",type="string",JSONPath=".status.atProvider.device"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,ucan},shortName=vau
+type VolumeAttachment struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VolumeAttachmentSpec   `json:"spec"`
+	Status VolumeAttachmentStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VolumeAttachmentList contains a list of VolumeAttachment
+type VolumeAttachmentList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VolumeAttachment `json:"items"`
+}
+
+// VolumeAttachment type metadata.
+var (
+	VolumeAttachmentKind             = reflect.TypeOf(VolumeAttachment{}).Name()
+	VolumeAttachmentGroupKind        = schema.GroupKind{Group: Group, Kind: VolumeAttachmentKind}.String()
+	VolumeAttachmentKindAPIVersion   = VolumeAttachmentKind + "." + SchemeGroupVersion.String()
+	VolumeAttachmentGroupVersionKind = SchemeGroupVersion.WithKind(VolumeAttachmentKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&VolumeAttachment{}, &VolumeAttachmentList{})
+}
@@ -26,6 +26,15 @@ func (l *VirtualMachineList) GetItems() []resource.Managed {
 	return items
 }
 
+// GetItems of this VolumeAttachmentList.
+func (l *VolumeAttachmentList) GetItems() []resource.Managed {
+	items := make([]resource.Managed, len(l.Items))
+	for i := range l.Items {
+		items[i] = &l.Items[i]
+	}
+	return items
+}
+
 // GetItems of this VolumeList.
 func (l *VolumeList) GetItems() []resource.Managed {
 	items := make([]resource.Managed, len(l.Items))
@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by angryjet. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	reference "github.com/crossplane/crossplane-runtime/pkg/reference"
+	errors "github.com/pkg/errors"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ResolveReferences of this VirtualMachine.
+func (mg *VirtualMachine) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	var rsp reference.ResolutionResponse
+	var err error
+
+	for i3 := 0; i3 < len(mg.Spec.ForProvider.BlockDeviceMapping); i3++ {
+		rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+			CurrentValue: mg.Spec.ForProvider.BlockDeviceMapping[i3].UUID,
+			Extract:      ReadyExternalName(),
+			Reference:    mg.Spec.ForProvider.BlockDeviceMapping[i3].VolumeRef,
+			Selector:     mg.Spec.ForProvider.BlockDeviceMapping[i3].VolumeSelector,
+			To: reference.To{
+				List:    &VolumeList{},
+				Managed: &Volume{},
+			},
+		})
+		if err != nil {
+			return errors.Wrap(err, "mg.Spec.ForProvider.BlockDeviceMapping[i3].UUID")
+		}
+		mg.Spec.ForProvider.BlockDeviceMapping[i3].UUID = rsp.ResolvedValue
+		mg.Spec.ForProvider.BlockDeviceMapping[i3].VolumeRef = rsp.ResolvedReference
+
+	}
+
+	return nil
+}
+
+// ResolveReferences of this VolumeAttachment.
+func (mg *VolumeAttachment) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	var rsp reference.ResolutionResponse
+	var err error
+
+	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.ServerID,
+		Extract:      ReadyExternalName(),
+		Reference:    mg.Spec.ForProvider.ServerRef,
+		Selector:     mg.Spec.ForProvider.ServerSelector,
+		To: reference.To{
+			List:    &VirtualMachineList{},
+			Managed: &VirtualMachine{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.ServerID")
+	}
+	mg.Spec.ForProvider.ServerID = rsp.ResolvedValue
+	mg.Spec.ForProvider.ServerRef = rsp.ResolvedReference
+
+	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.VolumeID,
+		Extract:      ReadyExternalName(),
+		Reference:    mg.Spec.ForProvider.VolumeRef,
+		Selector:     mg.Spec.ForProvider.VolumeSelector,
+		To: reference.To{
+			List:    &VolumeList{},
+			Managed: &Volume{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.VolumeID")
+	}
+	mg.Spec.ForProvider.VolumeID = rsp.ResolvedValue
+	mg.Spec.ForProvider.VolumeRef = rsp.ResolvedReference
+
+	return nil
+}
@@ -29,6 +29,38 @@ import (
 type ProviderConfigSpec struct {
 	// Credentials required to authenticate to this provider.
 	Credentials ProviderCredentials `json:"credentials"`
+
+	// Endpoints of the UCAN services. Services that are not listed are
+	// reached through their in-cluster apiserver addresses.
+	// +optional
+	Endpoints *ServiceEndpoints `json:"endpoints,omitempty"`
+}
+
+// ServiceEndpoints is the catalog of UCAN service endpoints.
+type ServiceEndpoints struct {
+	// VirtualMachine service endpoint.
+	// +optional
+	VirtualMachine *ServiceEndpoint `json:"virtualmachine,omitempty"`
+
+	// Volume service endpoint.
+	// +optional
+	Volume *ServiceEndpoint `json:"volume,omitempty"`
+
+	// Network service endpoint.
+	// +optional
+	Network *ServiceEndpoint `json:"network,omitempty"`
+}
+
+// A ServiceEndpoint locates a single UCAN service.
+type ServiceEndpoint struct {
+	// URL of the service, e.g. http://virtualmachine.ucan.ustack.com.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+
+	// PathPrefix is prepended to every API path of the service, e.g.
+	// /virtualmachine when the service is reached through a shared gateway.
+	// +optional
+	PathPrefix string `json:"pathPrefix,omitempty"`
 }
 
 // ProviderCredentials required to authenticate.
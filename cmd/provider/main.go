@@ -44,6 +44,7 @@ import (
 	"github.com/crossplane/provider-ucan/apis/v1alpha1"
 	ucan "github.com/crossplane/provider-ucan/internal/controller"
 	"github.com/crossplane/provider-ucan/internal/features"
+	"github.com/crossplane/provider-ucan/pkg/httpclient"
 )
 
 func main() {
@@ -61,6 +62,14 @@ func main() {
 		namespace                  = app.Flag("namespace", "Namespace used to set as default scope in default secret store config.").Default("crossplane-system").Envar("POD_NAMESPACE").String()
 		enableExternalSecretStores = app.Flag("enable-external-secret-stores", "Enable support for ExternalSecretStores.").Default("false").Envar("ENABLE_EXTERNAL_SECRET_STORES").Bool()
 		enableManagementPolicies   = app.Flag("enable-management-policies", "Enable support for Management Policies.").Default("false").Envar("ENABLE_MANAGEMENT_POLICIES").Bool()
+
+		httpMaxIdleConns          = app.Flag("http-max-idle-conns", "Maximum number of idle connections to the UCAN services across all hosts.").Default("100").Int()
+		httpMaxIdleConnsPerHost   = app.Flag("http-max-idle-conns-per-host", "Maximum number of idle connections kept to a single UCAN service.").Default("20").Int()
+		httpMaxConnsPerHost       = app.Flag("http-max-conns-per-host", "Maximum number of connections to a single UCAN service. Zero means no limit.").Default("0").Int()
+		httpIdleConnTimeout       = app.Flag("http-idle-conn-timeout", "How long an idle connection to a UCAN service is kept open.").Default("90s").Duration()
+		httpResponseHeaderTimeout = app.Flag("http-response-header-timeout", "How long to wait for the response headers of a UCAN service. Zero means no limit.").Default("0s").Duration()
+		httpEnableHTTP2           = app.Flag("http-enable-http2", "Negotiate HTTP/2 with UCAN services reached over https.").Default("false").Bool()
+		httpDisableCompression    = app.Flag("http-disable-compression", "Do not request gzip compressed responses from UCAN services.").Default("false").Bool()
 	)
 	kingpin.MustParse(app.Parse(os.Args[1:]))
 
@@ -73,6 +82,16 @@ func main() {
 		ctrl.SetLogger(zl)
 	}
 
+	to := httpclient.DefaultTransportOptions()
+	to.MaxIdleConns = *httpMaxIdleConns
+	to.MaxIdleConnsPerHost = *httpMaxIdleConnsPerHost
+	to.MaxConnsPerHost = *httpMaxConnsPerHost
+	to.IdleConnTimeout = *httpIdleConnTimeout
+	to.ResponseHeaderTimeout = *httpResponseHeaderTimeout
+	to.EnableHTTP2 = *httpEnableHTTP2
+	to.DisableCompression = *httpDisableCompression
+	httpclient.SetTransportOptions(to)
+
 	cfg, err := ctrl.GetConfig()
 	kingpin.FatalIfError(err, "Cannot get API server rest config")
 
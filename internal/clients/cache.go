@@ -0,0 +1,146 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"sync"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apisv1alpha1 "github.com/crossplane/provider-ucan/apis/v1alpha1"
+	"github.com/crossplane/provider-ucan/pkg/httpclient"
+	"github.com/crossplane/provider-ucan/pkg/ucansdk"
+)
+
+const (
+	errGetPC          = "cannot get ProviderConfig"
+	errGetCreds       = "cannot get credentials"
+	errGetSecret      = "cannot get credentials secret"
+	errNoSecretRef    = "no credentials secret reference was provided"
+	errUnmarshalCreds = "cannot unmarshal credentials"
+)
+
+// A NewClientFn builds a UCAN client from credentials and endpoints.
+type NewClientFn func(creds []byte, endpoints ucansdk.Endpoints) (*ucansdk.Client, error)
+
+// NewUcanClient builds a UCAN client from JSON encoded SignCertificate
+// credentials.
+func NewUcanClient(creds []byte, endpoints ucansdk.Endpoints) (*ucansdk.Client, error) {
+	var signCertificate httpclient.SignCertificate
+	if err := json.Unmarshal(creds, &signCertificate); err != nil {
+		return nil, errors.Wrap(err, errUnmarshalCreds)
+	}
+	cli := httpclient.NewHttpClient(signCertificate, httpclient.WithHeader("Content-Type", "application/json"))
+	return ucansdk.NewClient(cli, endpoints), nil
+}
+
+type entry struct {
+	version string
+	client  *ucansdk.Client
+}
+
+// A Cache holds one UCAN client per ProviderConfig name. A cached client is
+// replaced when its ProviderConfig is recreated or its spec or credentials
+// change, and dropped when its ProviderConfig is deleted.
+type Cache struct {
+	newClient NewClientFn
+
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+// NewCache returns an empty Cache that builds clients with fn.
+func NewCache(fn NewClientFn) *Cache {
+	return &Cache{newClient: fn, entries: map[string]entry{}}
+}
+
+var defaultCache = NewCache(NewUcanClient)
+
+// DefaultCache returns the Cache shared by all UCAN controllers.
+func DefaultCache() *Cache {
+	return defaultCache
+}
+
+// Get returns the client for the ProviderConfig referenced by mg. The cache
+// saves building a client, not reading its configuration: every call still
+// gets the ProviderConfig and its credentials, e.g. a Secret, to detect
+// changes to either.
+func (c *Cache) Get(ctx context.Context, kube client.Client, mg resource.Managed) (*ucansdk.Client, error) {
+	name := mg.GetProviderConfigReference().Name
+	pc := &apisv1alpha1.ProviderConfig{}
+	if err := kube.Get(ctx, types.NamespacedName{Name: name}, pc); err != nil {
+		if kerrors.IsNotFound(err) {
+			c.mu.Lock()
+			delete(c.entries, name)
+			c.mu.Unlock()
+		}
+		return nil, errors.Wrap(err, errGetPC)
+	}
+
+	creds, credsVersion, err := credentials(ctx, kube, pc.Spec.Credentials)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetCreds)
+	}
+	version := string(pc.GetUID()) + "/" + strconv.FormatInt(pc.GetGeneration(), 10) + "/" + credsVersion
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[name]; ok && e.version == version {
+		return e.client, nil
+	}
+	cl, err := c.newClient(creds, Endpoints(pc.Spec))
+	if err != nil {
+		return nil, err
+	}
+	c.entries[name] = entry{version: version, client: cl}
+	return cl, nil
+}
+
+// credentials returns the credentials described by cd, and a version that
+// changes whenever they do. Secrets are versioned by their resourceVersion,
+// other sources by the hash of their content.
+func credentials(ctx context.Context, kube client.Client, cd apisv1alpha1.ProviderCredentials) ([]byte, string, error) {
+	if cd.Source != xpv1.CredentialsSourceSecret {
+		data, err := resource.CommonCredentialExtractor(ctx, cd.Source, kube, cd.CommonCredentialSelectors)
+		if err != nil {
+			return nil, "", err
+		}
+		sum := sha256.Sum256(data)
+		return data, hex.EncodeToString(sum[:]), nil
+	}
+
+	ref := cd.SecretRef
+	if ref == nil {
+		return nil, "", errors.New(errNoSecretRef)
+	}
+	s := &corev1.Secret{}
+	if err := kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+		return nil, "", errors.Wrap(err, errGetSecret)
+	}
+	return s.Data[ref.Key], string(s.GetUID()) + "@" + s.GetResourceVersion(), nil
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apisv1alpha1 "github.com/crossplane/provider-ucan/apis/v1alpha1"
+	"github.com/crossplane/provider-ucan/pkg/ucansdk"
+)
+
+func TestCacheGet(t *testing.T) {
+	pc := &apisv1alpha1.ProviderConfig{
+		Spec: apisv1alpha1.ProviderConfigSpec{
+			Credentials: apisv1alpha1.ProviderCredentials{
+				Source: xpv1.CredentialsSourceSecret,
+				CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+					SecretRef: &xpv1.SecretKeySelector{
+						SecretReference: xpv1.SecretReference{Namespace: "crossplane-system", Name: "creds"},
+						Key:             "credentials",
+					},
+				},
+			},
+		},
+	}
+	pc.SetUID("pc-uid")
+	pc.SetGeneration(1)
+
+	secretVersion := "1"
+	deleted := false
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			switch o := obj.(type) {
+			case *apisv1alpha1.ProviderConfig:
+				if deleted {
+					return kerrors.NewNotFound(schema.GroupResource{}, "default")
+				}
+				pc.DeepCopyInto(o)
+			case *corev1.Secret:
+				o.SetUID("secret-uid")
+				o.SetResourceVersion(secretVersion)
+				o.Data = map[string][]byte{"credentials": []byte(`{"accessKeyId":"ak","secretAccessKey":"sk"}`)}
+			}
+			return nil
+		},
+	}
+
+	built := 0
+	c := NewCache(func(creds []byte, endpoints ucansdk.Endpoints) (*ucansdk.Client, error) {
+		built++
+		return NewUcanClient(creds, endpoints)
+	})
+	mg := &fake.Managed{ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &xpv1.Reference{Name: "default"}}}
+
+	get := func() *ucansdk.Client {
+		t.Helper()
+		cl, err := c.Get(context.Background(), kube, mg)
+		if err != nil {
+			t.Fatalf("Get(...): %v", err)
+		}
+		return cl
+	}
+
+	first := get()
+	if second := get(); second != first || built != 1 {
+		t.Errorf("Get(...): want the cached client to be reused, built %d clients", built)
+	}
+
+	secretVersion = "2"
+	if third := get(); third == first || built != 2 {
+		t.Errorf("Get(...): want a new client after the secret changed, built %d clients", built)
+	}
+
+	pc.SetGeneration(2)
+	pc.Spec.Endpoints = &apisv1alpha1.ServiceEndpoints{Volume: &apisv1alpha1.ServiceEndpoint{URL: "http://volume.ucan.ustack.com", PathPrefix: "/volume"}}
+	if fourth := get(); fourth.Endpoints.Volume.BaseURL != "http://volume.ucan.ustack.com" || built != 3 {
+		t.Errorf("Get(...): want a new client after the ProviderConfig changed, built %d clients", built)
+	}
+
+	pc.SetUID("new-pc-uid")
+	if fifth := get(); fifth == first || built != 4 || len(c.entries) != 1 {
+		t.Errorf("Get(...): want the client of a recreated ProviderConfig to replace the old one, built %d clients, cached %d", built, len(c.entries))
+	}
+
+	deleted = true
+	if _, err := c.Get(context.Background(), kube, mg); err == nil || len(c.entries) != 0 {
+		t.Errorf("Get(...): want the client of a deleted ProviderConfig to be dropped, got error %v, cached %d", err, len(c.entries))
+	}
+}
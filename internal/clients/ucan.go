@@ -0,0 +1,45 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clients contains helpers shared by the UCAN controllers to build
+// clients from a ProviderConfig.
+package clients
+
+import (
+	apisv1alpha1 "github.com/crossplane/provider-ucan/apis/v1alpha1"
+	"github.com/crossplane/provider-ucan/pkg/ucansdk"
+)
+
+// Endpoints returns the UCAN service catalog configured by the supplied
+// ProviderConfig spec. Services that are not configured use the in-cluster
+// defaults.
+func Endpoints(spec apisv1alpha1.ProviderConfigSpec) ucansdk.Endpoints {
+	e := ucansdk.DefaultEndpoints()
+	if spec.Endpoints == nil {
+		return e
+	}
+	e.VirtualMachine = endpoint(spec.Endpoints.VirtualMachine, e.VirtualMachine)
+	e.Volume = endpoint(spec.Endpoints.Volume, e.Volume)
+	e.Network = endpoint(spec.Endpoints.Network, e.Network)
+	return e
+}
+
+func endpoint(se *apisv1alpha1.ServiceEndpoint, def ucansdk.Endpoint) ucansdk.Endpoint {
+	if se == nil || se.URL == "" {
+		return def
+	}
+	return ucansdk.Endpoint{BaseURL: se.URL, PathPrefix: se.PathPrefix}
+}
@@ -19,15 +19,14 @@ package floatingip
 import (
 	"context"
 	"encoding/json"
-	"fmt"
-	"net/http"
 
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/pkg/errors"
-	"k8s.io/apimachinery/pkg/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/connection"
@@ -39,6 +38,9 @@ import (
 
 	"github.com/crossplane/provider-ucan/apis/osgalaxy/v1alpha1"
 	apisv1alpha1 "github.com/crossplane/provider-ucan/apis/v1alpha1"
+	"github.com/crossplane/provider-ucan/internal/clients"
+	"github.com/crossplane/provider-ucan/internal/drift"
+	"github.com/crossplane/provider-ucan/internal/externalname"
 	"github.com/crossplane/provider-ucan/internal/features"
 	"github.com/crossplane/provider-ucan/pkg/httpclient"
 	"github.com/crossplane/provider-ucan/pkg/ucansdk"
@@ -47,30 +49,23 @@ import (
 const (
 	errNotFloatingip = "managed resource is not a Floatingip custom resource"
 	errTrackPCUsage  = "cannot track ProviderConfig usage"
-	errGetPC         = "cannot get ProviderConfig"
-	errGetCreds      = "cannot get credentials"
 	errNewClient     = "cannot create new Service"
 
+	// eipUUIDAnnotationKey held the UCAN ID before the external-name annotation
+	// was adopted. It is migrated by the LegacyAnnotationMigrator.
 	eipUUIDAnnotationKey = "ucan.io/eip-uuid"
 )
 
+// Keys of the connection details of a Floatingip.
+const (
+	keyID      = "id"
+	keyAddress = "address"
+)
+
 type UcanClient struct {
-	HttpClient *httpclient.HttpClient
+	*ucansdk.Client
 }
 
-var (
-	newUcanClient = func(credentials []byte) (*UcanClient, error) {
-		var signCertificate httpclient.SignCertificate
-		if err := json.Unmarshal(credentials, &signCertificate); err != nil {
-			fmt.Println("*************cannot get credentials*************")
-			return nil, err
-		}
-		cli := httpclient.NewHttpClient(signCertificate)
-		cli.SetHeader("Content-Type", "application/json")
-		return &UcanClient{HttpClient: cli}, nil
-	}
-)
-
 // Setup adds a controller that reconciles Floatingip managed resources.
 func Setup(mgr ctrl.Manager, o controller.Options) error {
 	name := managed.ControllerName(v1alpha1.FloatingipGroupKind)
@@ -80,16 +75,24 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
 	}
 
-	r := managed.NewReconciler(mgr,
-		resource.ManagedKind(v1alpha1.FloatingipGroupVersionKind),
+	log := o.Logger.WithValues("controller", name)
+	opts := []managed.ReconcilerOption{
 		managed.WithExternalConnecter(&connector{
-			kube:         mgr.GetClient(),
-			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
-			newServiceFn: newUcanClient}),
-		managed.WithLogger(o.Logger.WithValues("controller", name)),
+			kube:    mgr.GetClient(),
+			usage:   resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			clients: clients.DefaultCache(),
+			logger:  log}),
+		managed.WithInitializers(externalname.NewLegacyAnnotationMigrator(mgr.GetClient(), eipUUIDAnnotationKey)),
+		managed.WithLogger(log),
 		managed.WithPollInterval(o.PollInterval),
 		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-		managed.WithConnectionPublishers(cps...))
+		managed.WithConnectionPublishers(cps...),
+	}
+	if o.Features.Enabled(features.EnableAlphaManagementPolicies) {
+		opts = append(opts, managed.WithManagementPolicies())
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1alpha1.FloatingipGroupVersionKind), opts...)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
@@ -100,9 +103,10 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 }
 
 type connector struct {
-	kube         client.Client
-	usage        resource.Tracker
-	newServiceFn func(creds []byte) (*UcanClient, error)
+	kube    client.Client
+	usage   resource.Tracker
+	clients *clients.Cache
+	logger  logging.Logger
 }
 
 func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
@@ -115,30 +119,21 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errTrackPCUsage)
 	}
 
-	pc := &apisv1alpha1.ProviderConfig{}
-	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.GetProviderConfigReference().Name}, pc); err != nil {
-		return nil, errors.Wrap(err, errGetPC)
-	}
-
-	cd := pc.Spec.Credentials
-	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.kube, cd.CommonCredentialSelectors)
-	if err != nil {
-		return nil, errors.Wrap(err, errGetCreds)
-	}
-
-	svc, err := c.newServiceFn(data)
+	svc, err := c.clients.Get(ctx, c.kube, mg)
 	if err != nil {
 		return nil, errors.Wrap(err, errNewClient)
 	}
 
-	zl := zap.New(zap.UseDevMode(true))
-	log := logging.NewLogrLogger(zl.WithName("provider-eip"))
-	return &external{service: svc, logger: log}, nil
+	log := c.logger.WithValues("name", cr.GetName(), "project", cr.Spec.ForProvider.ProjectId, "externalID", meta.GetExternalName(cr))
+	return &external{service: &UcanClient{Client: svc.WithOptions(httpclient.WithLogger(log))}, logger: log}, nil
 }
 
 type external struct {
 	service *UcanClient
 	logger  logging.Logger
+
+	// diff is the drift found by the last call to Observe.
+	diff drift.Diff
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -147,45 +142,45 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.New(errNotFloatingip)
 	}
 
-	uuid, ok := cr.GetAnnotations()[eipUUIDAnnotationKey]
-	if !ok {
-		c.logger.Info("eip Resource Status", "msg", "uuid not found")
+	uuid := meta.GetExternalName(cr)
+	if uuid == "" {
+		c.logger.Debug("External resource has no ID")
 		return managed.ExternalObservation{
 			ResourceExists: false,
 		}, nil
 	}
 
-	c.service.HttpClient.SetHeader("X-UCAN-NS", cr.Spec.ForProvider.ProjectId)
-	eip, code, err := ucansdk.GetEip(c.service.HttpClient, uuid)
-	if err != nil {
-		c.logger.Info("eip Resource err", "msg", err)
-		return managed.ExternalObservation{}, errors.Wrap(err, "cannot get volume")
+	eip, err := ucansdk.GetEip(ctx, c.service.Client, cr.Spec.ForProvider.ProjectId, uuid)
+	if ucansdk.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
-	if code >= http.StatusBadRequest && code != http.StatusNotFound {
-		c.logger.Info("get Resource err", "code", code, "body", string(eip))
-		return managed.ExternalObservation{}, errors.New("cannot get eip")
+	if err != nil {
+		c.logger.Debug("Cannot get eip", "error", err)
+		return managed.ExternalObservation{}, errors.Wrap(err, "cannot get eip")
 	}
 
 	var response ucansdk.EipGetResponse
 	if err = json.Unmarshal(eip, &response); err != nil {
-		c.logger.Info("unmarshal err get Resource", "msg", err)
+		c.logger.Debug("Cannot unmarshal eip", "error", err)
 		return managed.ExternalObservation{}, errors.Wrap(err, "cannot unmarshal eip")
 	}
-	c.logger.Info("unmarshal Resource", "id", response.FloatingIps.ID, "name", response.FloatingIps.Name, "status", response.FloatingIps.Status)
-	resourceExists := code != http.StatusNotFound
-	cr.Status.AtProvider = v1alpha1.FloatingipObservation{
-		Status: response.FloatingIps.Status,
-	}
-	if resourceExists && response.FloatingIps.Status == "running" {
+	c.logger.Debug("Observed eip", "status", response.FloatingIps.Status)
+	cr.Status.AtProvider = generateObservation(response)
+	if response.FloatingIps.Status == "running" {
 		// 将状态置为可用
 		cr.SetConditions(xpv1.Available())
 	}
 
-	return managed.ExternalObservation{
-		ResourceExists:    resourceExists,
-		ResourceUpToDate:  true,
-		ConnectionDetails: managed.ConnectionDetails{},
-	}, nil
+	c.diff = generateDiff(cr, response)
+	if len(c.diff) > 0 {
+		c.logger.Debug("Observed drift", "diff", c.diff.String())
+	}
+	o, err := drift.Observation(cr, c.diff)
+	if err != nil {
+		return o, err
+	}
+	o.ConnectionDetails = connectionDetails(cr.Status.AtProvider)
+	return o, nil
 }
 
 func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
@@ -202,35 +197,29 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 			FloatingNetwork: cr.Spec.ForProvider.FloatingNetworkId,
 			Isp:             cr.Spec.ForProvider.Isp,
 			Bandwidth:       int(cr.Spec.ForProvider.Bandwidth),
-			Description:     cr.Spec.ForProvider.Description,
+			Description:     ptr.Deref(cr.Spec.ForProvider.Description, ""),
 			RouteId:         cr.Spec.ForProvider.RouteId,
+			QosPolicyId:     cr.Spec.ForProvider.QosPolicyId,
 		},
 	}
 	reqData, err := json.Marshal(req)
 	if err != nil {
-		c.logger.Info("Marshal err create Resource", "msg", err)
+		c.logger.Debug("Cannot marshal eip request", "error", err)
 		return managed.ExternalCreation{}, errors.Wrap(err, "cannot create eip")
 	}
-	c.service.HttpClient.SetHeader("X-UCAN-NS", cr.Spec.ForProvider.ProjectId)
-	eip, code, err := ucansdk.CreateEip(c.service.HttpClient, reqData)
+	eip, err := ucansdk.CreateEip(ctx, c.service.Client, reqData, cr.Spec.ForProvider.ProjectId)
 	if err != nil {
-		c.logger.Info("create Resource err", "msg", err)
+		c.logger.Debug("Cannot create eip", "error", err)
 		return managed.ExternalCreation{}, errors.Wrap(err, "cannot create eip")
 	}
-	if code >= http.StatusBadRequest {
-		c.logger.Info("create Resource err", "code", code, "body", string(eip))
-		return managed.ExternalCreation{}, errors.New("cannot create eip")
-	}
 	var response ucansdk.EipGetResponse
 	if err = json.Unmarshal(eip, &response); err != nil {
-		c.logger.Info("unmarshal err create Resource", "msg", err)
+		c.logger.Debug("Cannot unmarshal eip", "error", err)
 		return managed.ExternalCreation{}, errors.Wrap(err, "cannot unmarshal eip")
 	}
-	c.logger.Info("unmarshal Resource", "id", response.FloatingIps.ID, "name", response.FloatingIps.Name)
+	c.logger.Debug("Created eip", "externalID", response.FloatingIps.ID)
 
-	mg.SetAnnotations(map[string]string{
-		eipUUIDAnnotationKey: response.FloatingIps.ID,
-	})
+	meta.SetExternalName(cr, response.FloatingIps.ID)
 
 	return managed.ExternalCreation{
 		ConnectionDetails: managed.ConnectionDetails{},
@@ -243,11 +232,39 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, errors.New(errNotFloatingip)
 	}
 
-	fmt.Printf("Updating: %+v\n", cr.Spec.ForProvider)
+	diff := c.diff.Updatable()
+	c.logger.Debug("Updating eip", "diff", diff.String())
+	if len(diff) == 0 {
+		return managed.ExternalUpdate{}, nil
+	}
 
-	return managed.ExternalUpdate{
-		ConnectionDetails: managed.ConnectionDetails{},
-	}, nil
+	// Only send the fields that drifted, so that fields that are not set in
+	// the spec are left unchanged.
+	p := cr.Spec.ForProvider
+	var param ucansdk.UpdateEipReqParam
+	if diff.Has("name") {
+		param.Name = p.Name
+	}
+	if diff.Has("description") {
+		param.Description = p.Description
+	}
+	if diff.Has("qosPolicyId") {
+		param.QosPolicyId = p.QosPolicyId
+	}
+	if diff.Has("bandwidth") {
+		param.Bandwidth = int(p.Bandwidth)
+	}
+	reqData, err := json.Marshal(ucansdk.UpdateEipReq{FloatingIp: param})
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, "cannot update eip")
+	}
+	if _, err := ucansdk.UpdateEip(ctx, c.service.Client, p.ProjectId, meta.GetExternalName(cr), reqData); err != nil {
+		c.logger.Debug("Cannot update eip", "error", err)
+		return managed.ExternalUpdate{}, errors.Wrap(err, "cannot update eip")
+	}
+	c.logger.Debug("Updated eip")
+
+	return managed.ExternalUpdate{}, nil
 }
 
 func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
@@ -256,26 +273,78 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalDelete{}, errors.New(errNotFloatingip)
 	}
 
-	uuid, ok := cr.GetAnnotations()[eipUUIDAnnotationKey]
-	if !ok {
-		c.logger.Info("eip Resource Status", "name", cr.Name, "msg", "uuid not found")
-		return managed.ExternalDelete{}, errors.New("uuid not found")
+	uuid := meta.GetExternalName(cr)
+	if uuid == "" {
+		c.logger.Debug("External resource has no ID")
+		return managed.ExternalDelete{}, nil
 	}
-	c.service.HttpClient.SetHeader("X-UCAN-NS", cr.Spec.ForProvider.ProjectId)
-	body, code, err := ucansdk.DelEip(c.service.HttpClient, uuid)
-	if err != nil {
-		c.logger.Info("delete Resource err", "name", cr.Name, "msg", err)
+	_, err := ucansdk.DelEip(ctx, c.service.Client, cr.Spec.ForProvider.ProjectId, uuid)
+	if err != nil && !ucansdk.IsNotFound(err) {
+		c.logger.Debug("Cannot delete eip", "error", err)
 		return managed.ExternalDelete{}, errors.Wrap(err, "cannot delete eip")
 	}
-	if code != http.StatusNoContent {
-		c.logger.Info("delete Resource err", "name", cr.Name, "code", code, "body", string(body))
-		return managed.ExternalDelete{}, errors.New("cannot delete eip")
-	}
 
-	c.logger.Info("delete Resource", "name", cr.Name, "code", code)
+	c.logger.Debug("Deleted eip")
 	return managed.ExternalDelete{}, nil
 }
 
 func (c *external) Disconnect(ctx context.Context) error {
 	return nil
 }
+
+// generateObservation projects the UCAN floating IP onto the observable fields
+// of a Floatingip.
+func generateObservation(resp ucansdk.EipGetResponse) v1alpha1.FloatingipObservation {
+	e := resp.FloatingIps
+	o := v1alpha1.FloatingipObservation{
+		ID:                e.ID,
+		Name:              e.Name,
+		Description:       e.Description,
+		ProjectId:         e.ProjectID,
+		CellId:            e.CellId,
+		Isp:               e.Isp,
+		FloatingNetworkId: e.FloatingNetwork,
+		QosPolicyId:       e.QosPolicyId,
+		RouteId:           e.RouteId,
+		Bandwidth:         int64(e.Bandwidth),
+		Status:            e.Status,
+		FloatingIPAddress: ptr.Deref(e.FloatingIP, ""),
+		FixedIPAddress:    e.FixedIPAddress,
+		UserId:            e.UserID,
+	}
+	if !e.Created.IsZero() {
+		o.Created = &metav1.Time{Time: e.Created}
+	}
+	if !e.Updated.IsZero() {
+		o.Updated = &metav1.Time{Time: e.Updated}
+	}
+	return o
+}
+
+// connectionDetails returns the ID and the allocated address of a Floatingip.
+func connectionDetails(o v1alpha1.FloatingipObservation) managed.ConnectionDetails {
+	cd := managed.ConnectionDetails{keyID: []byte(o.ID)}
+	if o.FloatingIPAddress != "" {
+		cd[keyAddress] = []byte(o.FloatingIPAddress)
+	}
+	return cd
+}
+
+// generateDiff compares the parameters of a Floatingip with the UCAN floating
+// IP.
+func generateDiff(cr *v1alpha1.Floatingip, resp ucansdk.EipGetResponse) drift.Diff {
+	p := cr.Spec.ForProvider
+	e := resp.FloatingIps
+
+	var d drift.Diff
+	drift.Compare(&d, "name", p.Name, e.Name, drift.Updatable)
+	drift.ComparePtr(&d, "description", p.Description, e.Description, drift.Updatable)
+	drift.Compare(&d, "bandwidth", p.Bandwidth, int64(e.Bandwidth), drift.Updatable)
+	drift.Compare(&d, "qosPolicyId", p.QosPolicyId, e.QosPolicyId, drift.Updatable)
+	drift.Compare(&d, "projectId", p.ProjectId, e.ProjectID, drift.Immutable)
+	drift.Compare(&d, "cellId", p.CellId, e.CellId, drift.Immutable)
+	drift.Compare(&d, "isp", p.Isp, e.Isp, drift.Immutable)
+	drift.Compare(&d, "floatingNetworkId", p.FloatingNetworkId, e.FloatingNetwork, drift.Immutable)
+	drift.Compare(&d, "routeId", p.RouteId, e.RouteId, drift.Immutable)
+	return d
+}
@@ -18,13 +18,28 @@ package floatingip
 
 import (
 	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-ucan/apis/osgalaxy/v1alpha1"
+	"github.com/crossplane/provider-ucan/internal/drift"
+	"github.com/crossplane/provider-ucan/pkg/httpclient"
+	"github.com/crossplane/provider-ucan/pkg/ucansdk"
 )
 
 // Unlike many Kubernetes projects Crossplane does not use third party testing
@@ -35,40 +50,272 @@ import (
 // https://github.com/golang/go/wiki/TestComments
 // https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
 
-func TestObserve(t *testing.T) {
-	type fields struct {
-		service *UcanClient
+const eipJSON = `{"floatingips": {
+	"id": "eip-1",
+	"name": "web",
+	"description": "imported",
+	"project_id": "project-1",
+	"cell_id": "cell-1",
+	"isp": "bgp",
+	"floating_network_id": "net-1",
+	"qos_policy_id": "qos-1",
+	"route_id": "route-1",
+	"bandwidth": 10,
+	"status": "running",
+	"floating_ip_address": "203.0.113.5",
+	"fixed_ip_address": "10.0.0.5",
+	"created_at": "2025-01-02T03:04:05Z"
+}}`
+
+var floatingipObservation = v1alpha1.FloatingipObservation{
+	ID:                "eip-1",
+	Name:              "web",
+	Description:       "imported",
+	ProjectId:         "project-1",
+	CellId:            "cell-1",
+	Isp:               "bgp",
+	FloatingNetworkId: "net-1",
+	QosPolicyId:       "qos-1",
+	RouteId:           "route-1",
+	Bandwidth:         10,
+	Status:            "running",
+	FloatingIPAddress: "203.0.113.5",
+	FixedIPAddress:    "10.0.0.5",
+	Created:           &metav1.Time{Time: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)},
+}
+
+type eipModifier func(*v1alpha1.Floatingip)
+
+func withExternalName(n string) eipModifier {
+	return func(cr *v1alpha1.Floatingip) { meta.SetExternalName(cr, n) }
+}
+
+func withProjectId(p string) eipModifier {
+	return func(cr *v1alpha1.Floatingip) { cr.Spec.ForProvider.ProjectId = p }
+}
+
+func withBandwidth(v int64) eipModifier {
+	return func(cr *v1alpha1.Floatingip) { cr.Spec.ForProvider.Bandwidth = v }
+}
+
+func withManagementPolicies(p ...xpv1.ManagementAction) eipModifier {
+	return func(cr *v1alpha1.Floatingip) { cr.SetManagementPolicies(p) }
+}
+
+func withAtProvider(o v1alpha1.FloatingipObservation) eipModifier {
+	return func(cr *v1alpha1.Floatingip) { cr.Status.AtProvider = o }
+}
+
+func withConditions(c ...xpv1.Condition) eipModifier {
+	return func(cr *v1alpha1.Floatingip) { cr.SetConditions(c...) }
+}
+
+func floatingip(m ...eipModifier) *v1alpha1.Floatingip {
+	cr := &v1alpha1.Floatingip{}
+	for _, f := range m {
+		f(cr)
 	}
+	return cr
+}
 
+// newService returns a UcanClient whose endpoints are served by h.
+func newService(t *testing.T, h http.HandlerFunc) *UcanClient {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	ep := ucansdk.Endpoint{BaseURL: srv.URL}
+	cli := httpclient.NewHttpClient(httpclient.SignCertificate{AccessKeyID: "ak", SecretAccessKey: "sk"}, httpclient.WithRetryPolicy(httpclient.RetryPolicy{MaxAttempts: 1}))
+	return &UcanClient{Client: ucansdk.NewClient(cli, ucansdk.Endpoints{VirtualMachine: ep, Volume: ep, Network: ep})}
+}
+
+func TestObserve(t *testing.T) {
 	type args struct {
-		ctx context.Context
-		mg  resource.Managed
+		mg resource.Managed
 	}
 
 	type want struct {
+		mg  resource.Managed
 		o   managed.ExternalObservation
 		err error
 	}
 
 	cases := map[string]struct {
-		reason string
-		fields fields
-		args   args
-		want   want
+		reason  string
+		handler http.HandlerFunc
+		args    args
+		want    want
 	}{
-		// TODO: Add test cases.
+		"NoExternalName": {
+			reason: "A Floatingip without an external name should not exist.",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			args: args{mg: floatingip(withProjectId("project-1"))},
+			want: want{
+				mg: floatingip(withProjectId("project-1")),
+				o:  managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"NotFound": {
+			reason: "A Floatingip that UCAN cannot find should not exist.",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			args: args{mg: floatingip(withProjectId("project-1"), withExternalName("eip-1"))},
+			want: want{
+				mg: floatingip(withProjectId("project-1"), withExternalName("eip-1")),
+				o:  managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"GetError": {
+			reason: "Errors getting the Floatingip should be returned.",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusForbidden)
+			},
+			args: args{mg: floatingip(withProjectId("project-1"), withExternalName("eip-1"))},
+			want: want{
+				mg:  floatingip(withProjectId("project-1"), withExternalName("eip-1")),
+				err: errors.Wrap(&ucansdk.APIError{StatusCode: http.StatusForbidden}, "cannot get eip"),
+			},
+		},
+		"ObserveOnlyImport": {
+			reason: "An imported Floatingip should have its remote state reported in status.atProvider.",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet || r.URL.Path != "/v3/floatingips/eip-1" || r.Header.Get(httpclient.NamespaceHeader) != "project-1" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				_, _ = w.Write([]byte(eipJSON))
+			},
+			args: args{mg: floatingip(withProjectId("project-1"), withExternalName("eip-1"), withManagementPolicies(xpv1.ManagementActionObserve))},
+			want: want{
+				mg: floatingip(
+					withProjectId("project-1"),
+					withExternalName("eip-1"),
+					withManagementPolicies(xpv1.ManagementActionObserve),
+					withAtProvider(floatingipObservation),
+					withConditions(xpv1.Available()),
+				),
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ConnectionDetails: managed.ConnectionDetails{"id": []byte("eip-1"), "address": []byte("203.0.113.5")},
+					ResourceUpToDate:  true,
+				},
+			},
+		},
+		"UpdatableDrift": {
+			reason: "A Floatingip whose updatable parameters differ from UCAN should need an update.",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(eipJSON))
+			},
+			args: args{mg: floatingip(withProjectId("project-1"), withExternalName("eip-1"), withBandwidth(20))},
+			want: want{
+				mg: floatingip(withProjectId("project-1"), withExternalName("eip-1"), withBandwidth(20),
+					withAtProvider(floatingipObservation), withConditions(xpv1.Available())),
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ConnectionDetails: managed.ConnectionDetails{"id": []byte("eip-1"), "address": []byte("203.0.113.5")},
+					ResourceUpToDate:  false,
+					Diff:              "spec.forProvider.bandwidth: desired 20, observed 10",
+				},
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			e := external{service: tc.fields.service}
-			got, err := e.Observe(tc.args.ctx, tc.args.mg)
+			e := external{service: newService(t, tc.handler), logger: logging.NewNopLogger()}
+			got, err := e.Observe(context.Background(), tc.args.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
 			}
 			if diff := cmp.Diff(tc.want.o, got); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
 			}
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want managed resource, +got managed resource:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	type args struct {
+		mg   resource.Managed
+		diff drift.Diff
+	}
+
+	type want struct {
+		requests []string
+		err      error
+	}
+
+	cases := map[string]struct {
+		reason string
+		status int
+		args   args
+		want   want
+	}{
+		"NoDrift": {
+			reason: "No request should be sent if nothing has drifted.",
+			args:   args{mg: floatingip(withProjectId("project-1"), withExternalName("eip-1"))},
+		},
+		"BandwidthAndQos": {
+			reason: "Only the drifted fields should be sent.",
+			args: args{
+				mg: floatingip(withProjectId("project-1"), withExternalName("eip-1"), withBandwidth(20), func(cr *v1alpha1.Floatingip) {
+					cr.Spec.ForProvider.Name = "web"
+					cr.Spec.ForProvider.QosPolicyId = "qos-2"
+				}),
+				diff: drift.Diff{
+					{Path: "bandwidth", Desired: int64(20), Observed: int64(10), Mode: drift.Updatable},
+					{Path: "qosPolicyId", Desired: "qos-2", Observed: "qos-1", Mode: drift.Updatable},
+				},
+			},
+			want: want{requests: []string{`PUT /v3/floatingips/eip-1 project-1 {"floatingip":{"qos_policy_id":"qos-2","bandwidth":20}}`}},
+		},
+		"ClearDescription": {
+			reason: "An empty description should clear the description of the floating IP.",
+			args: args{
+				mg: floatingip(withProjectId("project-1"), withExternalName("eip-1"), func(cr *v1alpha1.Floatingip) {
+					cr.Spec.ForProvider.Description = ptr.To("")
+				}),
+				diff: drift.Diff{{Path: "description", Desired: "", Observed: "imported", Mode: drift.Updatable}},
+			},
+			want: want{requests: []string{`PUT /v3/floatingips/eip-1 project-1 {"floatingip":{"description":""}}`}},
+		},
+		"UpdateError": {
+			reason: "Errors updating the floating IP should be returned.",
+			status: http.StatusBadRequest,
+			args: args{
+				mg:   floatingip(withProjectId("project-1"), withExternalName("eip-1"), withBandwidth(20)),
+				diff: drift.Diff{{Path: "bandwidth", Desired: int64(20), Observed: int64(10), Mode: drift.Updatable}},
+			},
+			want: want{
+				requests: []string{`PUT /v3/floatingips/eip-1 project-1 {"floatingip":{"bandwidth":20}}`},
+				err:      errors.Wrap(&ucansdk.APIError{StatusCode: http.StatusBadRequest}, "cannot update eip"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var requests []string
+			svc := newService(t, func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				requests = append(requests, r.Method+" "+r.URL.Path+" "+r.Header.Get(httpclient.NamespaceHeader)+" "+string(body))
+				if tc.status != 0 {
+					w.WriteHeader(tc.status)
+				}
+			})
+			e := external{service: svc, logger: logging.NewNopLogger(), diff: tc.args.diff}
+			_, err := e.Update(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.requests, requests); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want requests, +got requests:\n%s\n", tc.reason, diff)
+			}
 		})
 	}
 }
@@ -24,6 +24,7 @@ import (
 	"github.com/crossplane/provider-ucan/internal/controller/floatingip"
 	"github.com/crossplane/provider-ucan/internal/controller/virtualmachine"
 	"github.com/crossplane/provider-ucan/internal/controller/volume"
+	"github.com/crossplane/provider-ucan/internal/controller/volumeattachment"
 )
 
 // Setup creates all Ucan controllers with the supplied logger and adds them to
@@ -34,6 +35,7 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		virtualmachine.Setup,
 		volume.Setup,
 		floatingip.Setup,
+		volumeattachment.Setup,
 	} {
 		if err := setup(mgr, o); err != nil {
 			return err
@@ -0,0 +1,215 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualmachine
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	"github.com/crossplane/provider-ucan/apis/osgalaxy/v1alpha1"
+	"github.com/crossplane/provider-ucan/internal/drift"
+	"github.com/crossplane/provider-ucan/pkg/ucansdk"
+)
+
+const (
+	errGetUserDataSecret    = "cannot get user data secret"
+	errGetUserDataConfigMap = "cannot get user data config map"
+	errGetPersonalitySecret = "cannot get personality secret"
+	errMissingKey           = "key %q is not set in %s/%s"
+
+	// userDataHashAnnotationKey and personalityHashAnnotationKey record the
+	// hashes of the sources of the contents a VirtualMachine was created
+	// with. They are kept in annotations because the reconciler persists only
+	// the annotations of a managed resource after Create.
+	userDataHashAnnotationKey    = "ucan.io/user-data-hash"
+	personalityHashAnnotationKey = "ucan.io/personality-hash"
+)
+
+// contents are the user data and personality files of a VirtualMachine,
+// base64-encoded as UCAN expects them.
+type contents struct {
+	userData    string
+	personality []ucansdk.FileInjection
+
+	// userDataSource and personalitySources are what the contents are hashed
+	// from. Contents read from a Secret or ConfigMap are represented by the
+	// version of the object, so that their hashes reveal nothing about them.
+	userDataSource     string
+	personalitySources []string
+}
+
+// resolveContents reads the user data and personality files of cr from the
+// Secrets and ConfigMaps they reference, if any, and renders the user data
+// template.
+func (c *external) resolveContents(ctx context.Context, cr *v1alpha1.VirtualMachine) (contents, error) {
+	p := cr.Spec.ForProvider
+	out := contents{userData: p.UserData, userDataSource: p.UserData}
+
+	switch {
+	case p.UserDataTemplate != nil:
+		data, src, err := c.renderUserData(ctx, cr)
+		if err != nil {
+			// A template that cannot be rendered is reported in its own
+			// condition, which persists until the template is fixed.
+			cr.SetConditions(v1alpha1.UserDataRenderFailed(err))
+			return contents{}, errors.Wrap(err, errRenderUserData)
+		}
+		cr.SetConditions(v1alpha1.UserDataRendered())
+		out.userData = base64.StdEncoding.EncodeToString(data)
+		out.userDataSource = string(src)
+	case p.UserDataSecretRef != nil:
+		data, version, err := c.secretKey(ctx, *p.UserDataSecretRef)
+		if err != nil {
+			return contents{}, errors.Wrap(err, errGetUserDataSecret)
+		}
+		out.userData = base64.StdEncoding.EncodeToString(data)
+		out.userDataSource = version
+	case p.UserDataConfigMapRef != nil:
+		data, version, err := c.configMapKey(ctx, *p.UserDataConfigMapRef)
+		if err != nil {
+			return contents{}, errors.Wrap(err, errGetUserDataConfigMap)
+		}
+		out.userData = base64.StdEncoding.EncodeToString(data)
+		out.userDataSource = version
+	}
+
+	for _, f := range p.Personality {
+		file := ucansdk.FileInjection{Path: f.Path, Contents: f.Contents}
+		src := f.Contents
+		if f.ContentsSecretRef != nil {
+			data, version, err := c.secretKey(ctx, *f.ContentsSecretRef)
+			if err != nil {
+				return contents{}, errors.Wrap(err, errGetPersonalitySecret)
+			}
+			file.Contents = base64.StdEncoding.EncodeToString(data)
+			src = version
+		}
+		out.personality = append(out.personality, file)
+		out.personalitySources = append(out.personalitySources, f.Path, src)
+	}
+	return out, nil
+}
+
+// diffContents records drift of the user data and personality files of cr
+// from those it was created with, e.g. because a referenced Secret changed.
+// Any update of a referenced Secret or ConfigMap is drift, even one that does
+// not change the key that is read.
+// UCAN cannot change either of them in place. A user data template is always
+// rendered, since the UserDataRendered condition set by Create is not persisted.
+func (c *external) diffContents(ctx context.Context, cr *v1alpha1.VirtualMachine, d *drift.Diff) error {
+	o := cr.Status.AtProvider
+	if meta.WasDeleted(cr) {
+		return nil
+	}
+	if o.UserDataHash == "" && o.PersonalityHash == "" && cr.Spec.ForProvider.UserDataTemplate == nil {
+		return nil
+	}
+	cs, err := c.resolveContents(ctx, cr)
+	if err != nil {
+		return err
+	}
+	if o.UserDataHash != "" {
+		drift.Compare(d, "userData", cs.userDataHash(), o.UserDataHash, drift.Immutable)
+	}
+	if o.PersonalityHash != "" {
+		drift.Compare(d, "personality", cs.personalityHash(), o.PersonalityHash, drift.Immutable)
+	}
+	return nil
+}
+
+// setContentHashes records the hashes of cs in the annotations of cr.
+func setContentHashes(cr *v1alpha1.VirtualMachine, cs contents) {
+	a := map[string]string{}
+	if h := cs.userDataHash(); h != "" {
+		a[userDataHashAnnotationKey] = h
+	}
+	if h := cs.personalityHash(); h != "" {
+		a[personalityHashAnnotationKey] = h
+	}
+	meta.AddAnnotations(cr, a)
+}
+
+// secretKey returns the value of a key of a Secret and its version.
+func (c *external) secretKey(ctx context.Context, ref xpv1.SecretKeySelector) ([]byte, string, error) {
+	s := &corev1.Secret{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+		return nil, "", err
+	}
+	data, ok := s.Data[ref.Key]
+	if !ok {
+		return nil, "", errors.Errorf(errMissingKey, ref.Key, ref.Namespace, ref.Name)
+	}
+	return data, version(s, ref.Key), nil
+}
+
+// configMapKey returns the value of a key of a ConfigMap and its version.
+func (c *external) configMapKey(ctx context.Context, ref v1alpha1.ConfigMapKeySelector) ([]byte, string, error) {
+	cm := &corev1.ConfigMap{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, cm); err != nil {
+		return nil, "", err
+	}
+	if data, ok := cm.Data[ref.Key]; ok {
+		return []byte(data), version(cm, ref.Key), nil
+	}
+	if data, ok := cm.BinaryData[ref.Key]; ok {
+		return data, version(cm, ref.Key), nil
+	}
+	return nil, "", errors.Errorf(errMissingKey, ref.Key, ref.Namespace, ref.Name)
+}
+
+// version identifies the revision of a key of o. It changes whenever o is
+// updated or recreated.
+func version(o metav1.Object, key string) string {
+	return fmt.Sprintf("%s/%s/%s", o.GetUID(), o.GetResourceVersion(), key)
+}
+
+// userDataHash returns the hash of the source of the user data, or an empty
+// string if there is none.
+func (cs contents) userDataHash() string {
+	if cs.userData == "" {
+		return ""
+	}
+	return hash(cs.userDataSource)
+}
+
+// personalityHash returns the hash of the paths and sources of the
+// personality files, or an empty string if there are none.
+func (cs contents) personalityHash() string {
+	if len(cs.personality) == 0 {
+		return ""
+	}
+	return hash(cs.personalitySources...)
+}
+
+func hash(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p + "\x00"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,277 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualmachine
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-ucan/apis/osgalaxy/v1alpha1"
+	"github.com/crossplane/provider-ucan/internal/drift"
+	"github.com/crossplane/provider-ucan/pkg/ucansdk"
+)
+
+var secretRef = &xpv1.SecretKeySelector{
+	SecretReference: xpv1.SecretReference{Name: "vm", Namespace: "default"},
+	Key:             "user-data",
+}
+
+var configMapRef = &v1alpha1.ConfigMapKeySelector{Name: "vm", Namespace: "default", Key: "user-data"}
+
+// mockGet returns a MockGetFn that serves data from any Secret or ConfigMap
+// at the supplied resource version.
+func mockGet(version string, data map[string]string) test.MockGetFn {
+	return func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+		obj.SetUID("uid-1")
+		obj.SetResourceVersion(version)
+		switch o := obj.(type) {
+		case *corev1.Secret:
+			o.Data = map[string][]byte{}
+			for k, v := range data {
+				o.Data[k] = []byte(v)
+			}
+		case *corev1.ConfigMap:
+			o.Data = data
+		}
+		return nil
+	}
+}
+
+func TestResolveContents(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		cs  contents
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		get    test.MockGetFn
+		params v1alpha1.VirtualMachineParameters
+		want   want
+	}{
+		"Inline": {
+			reason: "Inline contents should be passed through unchanged.",
+			params: v1alpha1.VirtualMachineParameters{
+				UserData:    "I2Nsb3VkLWNvbmZpZw==",
+				Personality: []v1alpha1.PersonalityParameters{{Path: "/etc/motd", Contents: "aGVsbG8="}},
+			},
+			want: want{cs: contents{
+				userData:           "I2Nsb3VkLWNvbmZpZw==",
+				personality:        []ucansdk.FileInjection{{Path: "/etc/motd", Contents: "aGVsbG8="}},
+				userDataSource:     "I2Nsb3VkLWNvbmZpZw==",
+				personalitySources: []string{"/etc/motd", "aGVsbG8="},
+			}},
+		},
+		"Secret": {
+			reason: "User data should be read from a Secret and encoded.",
+			get:    mockGet("1", map[string]string{"user-data": "#cloud-config"}),
+			params: v1alpha1.VirtualMachineParameters{UserData: "ignored", UserDataSecretRef: secretRef},
+			want:   want{cs: contents{userData: "I2Nsb3VkLWNvbmZpZw==", userDataSource: "uid-1/1/user-data"}},
+		},
+		"ConfigMap": {
+			reason: "User data should be read from a ConfigMap and encoded.",
+			get:    mockGet("1", map[string]string{"user-data": "#cloud-config"}),
+			params: v1alpha1.VirtualMachineParameters{UserDataConfigMapRef: configMapRef},
+			want:   want{cs: contents{userData: "I2Nsb3VkLWNvbmZpZw==", userDataSource: "uid-1/1/user-data"}},
+		},
+		"PersonalitySecret": {
+			reason: "Personality files should be read from a Secret and hashed from its version.",
+			get:    mockGet("1", map[string]string{"user-data": "hello"}),
+			params: v1alpha1.VirtualMachineParameters{
+				Personality: []v1alpha1.PersonalityParameters{{Path: "/etc/motd", ContentsSecretRef: secretRef}},
+			},
+			want: want{cs: contents{
+				personality:        []ucansdk.FileInjection{{Path: "/etc/motd", Contents: "aGVsbG8="}},
+				personalitySources: []string{"/etc/motd", "uid-1/1/user-data"},
+			}},
+		},
+		"MissingKey": {
+			reason: "A reference to a key that is not set should return an error.",
+			get:    mockGet("1", map[string]string{}),
+			params: v1alpha1.VirtualMachineParameters{UserDataSecretRef: secretRef},
+			want:   want{err: errors.Wrap(errors.New(`key "user-data" is not set in default/vm`), errGetUserDataSecret)},
+		},
+		"GetError": {
+			reason: "Errors getting a referenced Secret should be returned.",
+			get:    test.NewMockGetFn(errBoom),
+			params: v1alpha1.VirtualMachineParameters{
+				Personality: []v1alpha1.PersonalityParameters{{Path: "/etc/token", ContentsSecretRef: secretRef}},
+			},
+			want: want{err: errors.Wrap(errBoom, errGetPersonalitySecret)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{kube: &test.MockClient{MockGet: tc.get}, logger: logging.NewNopLogger()}
+			got, err := e.resolveContents(context.Background(), virtualMachine(withParameters(tc.params)))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.resolveContents(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cs, got, cmp.AllowUnexported(contents{})); diff != "" {
+				t.Errorf("\n%s\ne.resolveContents(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDiffContents(t *testing.T) {
+	created := contents{userData: "I2Nsb3VkLWNvbmZpZw==", userDataSource: "uid-1/1/user-data"}
+
+	cases := map[string]struct {
+		reason  string
+		version string
+		hash    string
+		want    drift.Diff
+	}{
+		"Unchanged": {
+			reason:  "User data whose Secret has not changed should not drift.",
+			version: "1",
+			hash:    created.userDataHash(),
+		},
+		"Changed": {
+			reason:  "A change to the referenced Secret should be immutable drift.",
+			version: "2",
+			hash:    created.userDataHash(),
+			want: drift.Diff{{
+				Path:     "userData",
+				Desired:  hash("uid-1/2/user-data"),
+				Observed: created.userDataHash(),
+				Mode:     drift.Immutable,
+			}},
+		},
+		"NoHash": {
+			reason:  "User data should not be compared if it was not recorded at creation.",
+			version: "2",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{kube: &test.MockClient{MockGet: mockGet(tc.version, map[string]string{"user-data": "#cloud-config"})}, logger: logging.NewNopLogger()}
+			cr := virtualMachine(
+				withParameters(v1alpha1.VirtualMachineParameters{UserDataSecretRef: secretRef}),
+				withAtProvider(v1alpha1.VirtualMachineObservation{UserDataHash: tc.hash}),
+			)
+			var d drift.Diff
+			if err := e.diffContents(context.Background(), cr, &d); err != nil {
+				t.Fatalf("\n%s\ne.diffContents(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, d); diff != "" {
+				t.Errorf("\n%s\ne.diffContents(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// apiServer stores a VirtualMachine the way the API server would: updates do
+// not change its status, and status updates change nothing else. Every Secret
+// holds data at version.
+type apiServer struct {
+	stored  *v1alpha1.VirtualMachine
+	version string
+	data    map[string]string
+}
+
+func (s *apiServer) client() *test.MockClient {
+	return &test.MockClient{
+		MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+			if cr, ok := obj.(*v1alpha1.VirtualMachine); ok {
+				s.stored.DeepCopyInto(cr)
+				return nil
+			}
+			return mockGet(s.version, s.data)(ctx, key, obj)
+		},
+		MockUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+			cr := obj.(*v1alpha1.VirtualMachine).DeepCopy()
+			cr.Status = s.stored.Status
+			s.stored = cr
+			cr.DeepCopyInto(obj.(*v1alpha1.VirtualMachine))
+			return nil
+		},
+		MockStatusUpdate: func(_ context.Context, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+			obj.(*v1alpha1.VirtualMachine).Status.DeepCopyInto(&s.stored.Status)
+			return nil
+		},
+	}
+}
+
+func TestReconcileContents(t *testing.T) {
+	svc := newService(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(serverJSON))
+	})
+	cr := virtualMachine(withParameters(v1alpha1.VirtualMachineParameters{UserDataSecretRef: secretRef}))
+	cr.SetName("web")
+	s := &apiServer{stored: cr, version: "1", data: map[string]string{"user-data": "#cloud-config"}}
+
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.SchemeBuilder.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme(...): %v", err)
+	}
+	kube := s.client()
+	r := managed.NewReconciler(&fake.Manager{Client: kube, Scheme: scheme},
+		resource.ManagedKind(v1alpha1.VirtualMachineGroupVersionKind),
+		managed.WithExternalConnecter(managed.ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (managed.ExternalClient, error) {
+			return &external{kube: kube, service: svc, logger: logging.NewNopLogger()}, nil
+		})),
+		managed.WithInitializers(),
+	)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "web"}}
+	reconcileTwice := func() {
+		t.Helper()
+		for i := 0; i < 2; i++ {
+			if _, err := r.Reconcile(context.Background(), req); err != nil {
+				t.Fatalf("r.Reconcile(...): %v", err)
+			}
+		}
+	}
+
+	// The first reconcile creates the server, and the second observes it.
+	reconcileTwice()
+	// The hash is of the version of the Secret, not of its data.
+	if got, want := s.stored.Status.AtProvider.UserDataHash, hash("uid-1/1/user-data"); got != want {
+		t.Errorf("r.Reconcile(...): want user data hash %q in status, got %q", want, got)
+	}
+	if c := s.stored.GetCondition(xpv1.TypeSynced); c.Reason != xpv1.ReasonReconcileSuccess {
+		t.Errorf("r.Reconcile(...): want a synced VirtualMachine, got %+v", c)
+	}
+
+	s.version, s.data = "2", map[string]string{"user-data": "#cloud-config\nhostname: web"}
+	reconcileTwice()
+	if c := s.stored.GetCondition(xpv1.TypeSynced); !strings.Contains(c.Message, "spec.forProvider.userData") {
+		t.Errorf("r.Reconcile(...): a change to the referenced Secret should be reported as drift, got %+v", c)
+	}
+}
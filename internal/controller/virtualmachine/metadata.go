@@ -0,0 +1,79 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualmachine
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-ucan/apis/osgalaxy/v1alpha1"
+	"github.com/crossplane/provider-ucan/pkg/ucansdk"
+)
+
+const errUpdateMetadata = "cannot update virtual machine metadata"
+
+// removedMetadata returns the keys that were applied to the server metadata
+// and have since been removed from spec.forProvider.metadata, but are still
+// set on the server. Keys UCAN adds to the server metadata are never removed.
+func removedMetadata(cr *v1alpha1.VirtualMachine, observed map[string]string) []string {
+	var removed []string
+	for _, k := range cr.Status.AtProvider.MetadataKeys {
+		if _, desired := cr.Spec.ForProvider.Metadata[k]; desired {
+			continue
+		}
+		if _, ok := observed[k]; ok {
+			removed = append(removed, k)
+		}
+	}
+	return removed
+}
+
+// metadataKeys returns the sorted keys of spec.forProvider.metadata.
+func metadataKeys(cr *v1alpha1.VirtualMachine) []string {
+	if len(cr.Spec.ForProvider.Metadata) == 0 {
+		return nil
+	}
+	return slices.Sorted(maps.Keys(cr.Spec.ForProvider.Metadata))
+}
+
+// updateMetadata merges spec.forProvider.metadata into the server metadata,
+// and deletes the removed keys returned by removedMetadata.
+func (c *external) updateMetadata(ctx context.Context, cr *v1alpha1.VirtualMachine, removed []string) error {
+	uuid := meta.GetExternalName(cr)
+	if len(cr.Spec.ForProvider.Metadata) > 0 {
+		reqData, err := json.Marshal(ucansdk.ServerMetadataReq{Metadata: cr.Spec.ForProvider.Metadata})
+		if err != nil {
+			return errors.Wrap(err, errUpdateMetadata)
+		}
+		if _, err := ucansdk.UpdateVmMetadata(ctx, c.service.Client, uuid, reqData); err != nil {
+			c.logger.Debug("Cannot update virtual machine metadata", "error", err)
+			return errors.Wrap(err, errUpdateMetadata)
+		}
+	}
+	for _, k := range removed {
+		if _, err := ucansdk.DeleteVmMetadataItem(ctx, c.service.Client, uuid, k); err != nil && !ucansdk.IsNotFound(err) {
+			c.logger.Debug("Cannot delete virtual machine metadata item", "key", k, "error", err)
+			return errors.Wrap(err, errUpdateMetadata)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualmachine
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-ucan/apis/osgalaxy/v1alpha1"
+	"github.com/crossplane/provider-ucan/pkg/ucansdk"
+)
+
+const errPower = "cannot change power state of virtual machine"
+
+// powerState returns the power state of a server with the supplied status, or
+// an empty string if the server is transitioning between states.
+func powerState(status string) string {
+	switch {
+	case statusIs(status, ucansdk.ServerStatusRunning), statusIs(status, ucansdk.ServerStatusActive):
+		return v1alpha1.PowerStateRunning
+	case statusIs(status, ucansdk.ServerStatusShutoff), statusIs(status, v1alpha1.PowerStateStopped):
+		return v1alpha1.PowerStateStopped
+	case statusIs(status, ucansdk.ServerStatusPaused):
+		return v1alpha1.PowerStatePaused
+	case statusIs(status, ucansdk.ServerStatusSuspended):
+		return v1alpha1.PowerStateSuspended
+	}
+	return ""
+}
+
+// powerAction returns the server action that moves a server from the observed
+// to the desired power state. Servers that are stopped, paused or suspended
+// are returned to Running before they move to another state, so reaching the
+// desired state may take two actions.
+func powerAction(observed, desired string) string {
+	if desired == "" || observed == "" || observed == desired {
+		return ""
+	}
+	switch observed {
+	case v1alpha1.PowerStateRunning:
+		switch desired {
+		case v1alpha1.PowerStateStopped:
+			return ucansdk.ActionStop
+		case v1alpha1.PowerStatePaused:
+			return ucansdk.ActionPause
+		case v1alpha1.PowerStateSuspended:
+			return ucansdk.ActionSuspend
+		}
+	case v1alpha1.PowerStateStopped:
+		return ucansdk.ActionStart
+	case v1alpha1.PowerStatePaused:
+		return ucansdk.ActionUnpause
+	case v1alpha1.PowerStateSuspended:
+		return ucansdk.ActionResume
+	}
+	return ""
+}
+
+// power issues the server action returned by powerAction. Power state changes
+// are asynchronous, so a server that UCAN reports as already transitioning is
+// not an error; the action is issued again if it is still needed once the
+// transition has completed.
+func (c *external) power(ctx context.Context, cr *v1alpha1.VirtualMachine, action string) error {
+	reqData, err := ucansdk.NewServerAction(action, nil)
+	if err != nil {
+		return errors.Wrap(err, errPower)
+	}
+	_, err = ucansdk.VmAction(ctx, c.service.Client, meta.GetExternalName(cr), reqData)
+	switch {
+	case ucansdk.IsConflict(err):
+		c.logger.Debug("Virtual machine is changing power state", "action", action, "error", err)
+		return nil
+	case err != nil:
+		c.logger.Debug("Cannot change power state of virtual machine", "action", action, "error", err)
+		return errors.Wrap(err, errPower)
+	}
+	c.logger.Debug("Requested power state change of virtual machine", "action", action)
+	return nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualmachine
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+
+	"github.com/crossplane/provider-ucan/apis/osgalaxy/v1alpha1"
+	"github.com/crossplane/provider-ucan/pkg/ucansdk"
+)
+
+func TestPowerAction(t *testing.T) {
+	cases := map[string]struct {
+		status  string
+		desired string
+		want    string
+	}{
+		"Unmanaged":          {status: "SHUTOFF", desired: "", want: ""},
+		"Converged":          {status: "PAUSED", desired: v1alpha1.PowerStatePaused, want: ""},
+		"Transitioning":      {status: "REBOOT", desired: v1alpha1.PowerStateStopped, want: ""},
+		"Stop":               {status: "Running", desired: v1alpha1.PowerStateStopped, want: ucansdk.ActionStop},
+		"Pause":              {status: "ACTIVE", desired: v1alpha1.PowerStatePaused, want: ucansdk.ActionPause},
+		"Suspend":            {status: "Running", desired: v1alpha1.PowerStateSuspended, want: ucansdk.ActionSuspend},
+		"Start":              {status: "SHUTOFF", desired: v1alpha1.PowerStateRunning, want: ucansdk.ActionStart},
+		"Unpause":            {status: "PAUSED", desired: v1alpha1.PowerStateRunning, want: ucansdk.ActionUnpause},
+		"Resume":             {status: "SUSPENDED", desired: v1alpha1.PowerStateRunning, want: ucansdk.ActionResume},
+		"StartBeforePausing": {status: "SHUTOFF", desired: v1alpha1.PowerStatePaused, want: ucansdk.ActionStart},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := powerAction(powerState(tc.status), tc.desired); got != tc.want {
+				t.Errorf("powerAction(%q, %q): want %q, got %q", tc.status, tc.desired, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestObservePowerState(t *testing.T) {
+	svc := newService(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(serverJSON))
+	})
+	e := external{service: svc, logger: logging.NewNopLogger()}
+	cr := virtualMachine(withExternalName("vm-1"), withParameters(v1alpha1.VirtualMachineParameters{PowerState: v1alpha1.PowerStateStopped}))
+
+	got, err := e.Observe(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("e.Observe(...): %v", err)
+	}
+	if diff := cmp.Diff(managed.ExternalObservation{ResourceExists: true, ConnectionDetails: serverConnectionDetails}, got); diff != "" {
+		t.Errorf("e.Observe(...): -want, +got:\n%s", diff)
+	}
+	if e.powerAction != ucansdk.ActionStop {
+		t.Errorf("e.Observe(...): want power action %q, got %q", ucansdk.ActionStop, e.powerAction)
+	}
+	if len(cr.Status.Conditions) != 0 {
+		t.Errorf("e.Observe(...): a running VirtualMachine that should be stopped should not be available, got %v", cr.Status.Conditions)
+	}
+}
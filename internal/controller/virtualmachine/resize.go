@@ -0,0 +1,126 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualmachine
+
+import (
+	"context"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-ucan/apis/osgalaxy/v1alpha1"
+	"github.com/crossplane/provider-ucan/pkg/ucansdk"
+)
+
+const errResize = "cannot resize virtual machine"
+
+func statusIs(status, want string) bool {
+	return strings.EqualFold(status, want)
+}
+
+// observeResize records the progress of a flavor change in the status of cr,
+// and returns the server action needed to converge on spec.forProvider.flavorRef,
+// if any. A resize is confirmed once the server reaches VERIFY_RESIZE with the
+// desired flavor, and reverted if the server fails while resizing. A resize
+// that was reverted is not retried until the desired flavor changes.
+func observeResize(cr *v1alpha1.VirtualMachine, resp ucansdk.ServerResp) string {
+	desired := cr.Spec.ForProvider.FlavorRef
+	s := resp.Server
+	r := cr.Status.AtProvider.Resize
+
+	switch {
+	case statusIs(s.Status, ucansdk.ServerStatusResize):
+		if r != nil {
+			r.Progress = int64(s.Progress)
+		}
+		return ""
+	case statusIs(s.Status, ucansdk.ServerStatusVerifyResize):
+		if desired == "" || s.Flavor.ID == desired {
+			return ucansdk.ActionConfirmResize
+		}
+		return ucansdk.ActionRevertResize
+	case statusIs(s.Status, ucansdk.ServerStatusError):
+		if r != nil && (r.Phase == v1alpha1.ResizePhaseResizing || r.Phase == v1alpha1.ResizePhaseConfirming) {
+			return ucansdk.ActionRevertResize
+		}
+		return ""
+	}
+
+	if r != nil {
+		switch r.Phase {
+		case v1alpha1.ResizePhaseResizing, v1alpha1.ResizePhaseConfirming:
+			if s.Flavor.ID == r.ToFlavorRef {
+				r.Phase = v1alpha1.ResizePhaseConfirmed
+				cr.SetConditions(v1alpha1.ResizeConfirmed(r.ToFlavorRef))
+				break
+			}
+			// The server settled on its original flavor without ever
+			// reaching VERIFY_RESIZE, so UCAN rejected the resize.
+			r.Phase = v1alpha1.ResizePhaseReverted
+			cr.SetConditions(v1alpha1.ResizeReverted(r.FromFlavorRef, r.ToFlavorRef))
+		case v1alpha1.ResizePhaseReverting:
+			r.Phase = v1alpha1.ResizePhaseReverted
+			cr.SetConditions(v1alpha1.ResizeReverted(r.FromFlavorRef, r.ToFlavorRef))
+		}
+		r.Progress = 0
+	}
+
+	if desired == "" || s.Flavor.ID == desired {
+		return ""
+	}
+	if r != nil && r.Phase == v1alpha1.ResizePhaseReverted && r.ToFlavorRef == desired {
+		return ""
+	}
+	return ucansdk.ActionResize
+}
+
+// resize issues the server action returned by observeResize.
+func (c *external) resize(ctx context.Context, cr *v1alpha1.VirtualMachine, action string) error {
+	var params any
+	if action == ucansdk.ActionResize {
+		params = ucansdk.ResizeParams{FlavorRef: cr.Spec.ForProvider.FlavorRef}
+	}
+	reqData, err := ucansdk.NewServerAction(action, params)
+	if err != nil {
+		return errors.Wrap(err, errResize)
+	}
+	if _, err := ucansdk.VmAction(ctx, c.service.Client, meta.GetExternalName(cr), reqData); err != nil {
+		c.logger.Debug("Cannot resize virtual machine", "action", action, "error", err)
+		return errors.Wrap(err, errResize)
+	}
+	c.logger.Debug("Requested resize of virtual machine", "action", action)
+
+	o := &cr.Status.AtProvider
+	if o.Resize == nil {
+		o.Resize = &v1alpha1.ResizeObservation{FromFlavorRef: o.FlavorRef, ToFlavorRef: o.FlavorRef}
+	}
+	switch action {
+	case ucansdk.ActionResize:
+		o.Resize = &v1alpha1.ResizeObservation{
+			FromFlavorRef: o.FlavorRef,
+			ToFlavorRef:   cr.Spec.ForProvider.FlavorRef,
+			Phase:         v1alpha1.ResizePhaseResizing,
+		}
+		cr.SetConditions(v1alpha1.Resizing(o.Resize.FromFlavorRef, o.Resize.ToFlavorRef))
+	case ucansdk.ActionConfirmResize:
+		o.Resize.Phase = v1alpha1.ResizePhaseConfirming
+	case ucansdk.ActionRevertResize:
+		o.Resize.Phase = v1alpha1.ResizePhaseReverting
+	}
+	return nil
+}
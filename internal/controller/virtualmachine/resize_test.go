@@ -0,0 +1,190 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualmachine
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-ucan/apis/osgalaxy/v1alpha1"
+	"github.com/crossplane/provider-ucan/pkg/ucansdk"
+)
+
+func withFlavorRef(f string) vmModifier {
+	return func(cr *v1alpha1.VirtualMachine) { cr.Spec.ForProvider.FlavorRef = f }
+}
+
+func withResize(r *v1alpha1.ResizeObservation) vmModifier {
+	return func(cr *v1alpha1.VirtualMachine) { cr.Status.AtProvider.Resize = r }
+}
+
+func server(status, flavor string, progress int) ucansdk.ServerResp {
+	var s ucansdk.ServerResp
+	s.Server.Status = status
+	s.Server.Flavor.ID = flavor
+	s.Server.Progress = progress
+	return s
+}
+
+func TestObserveResize(t *testing.T) {
+	type want struct {
+		action string
+		cr     *v1alpha1.VirtualMachine
+	}
+
+	cases := map[string]struct {
+		reason string
+		cr     *v1alpha1.VirtualMachine
+		server ucansdk.ServerResp
+		want   want
+	}{
+		"UpToDate": {
+			reason: "No action is needed if the server has the desired flavor.",
+			cr:     virtualMachine(withFlavorRef("flavor-1")),
+			server: server("Running", "flavor-1", 0),
+			want:   want{cr: virtualMachine(withFlavorRef("flavor-1"))},
+		},
+		"Resize": {
+			reason: "A server with another flavor should be resized.",
+			cr:     virtualMachine(withFlavorRef("flavor-2")),
+			server: server("Running", "flavor-1", 0),
+			want:   want{action: ucansdk.ActionResize, cr: virtualMachine(withFlavorRef("flavor-2"))},
+		},
+		"InProgress": {
+			reason: "The progress of a running resize should be recorded.",
+			cr: virtualMachine(withFlavorRef("flavor-2"),
+				withResize(&v1alpha1.ResizeObservation{FromFlavorRef: "flavor-1", ToFlavorRef: "flavor-2", Phase: v1alpha1.ResizePhaseResizing})),
+			server: server("RESIZE", "flavor-1", 40),
+			want: want{cr: virtualMachine(withFlavorRef("flavor-2"),
+				withResize(&v1alpha1.ResizeObservation{FromFlavorRef: "flavor-1", ToFlavorRef: "flavor-2", Phase: v1alpha1.ResizePhaseResizing, Progress: 40}))},
+		},
+		"Confirm": {
+			reason: "A resize to the desired flavor should be confirmed.",
+			cr:     virtualMachine(withFlavorRef("flavor-2")),
+			server: server("VERIFY_RESIZE", "flavor-2", 100),
+			want:   want{action: ucansdk.ActionConfirmResize, cr: virtualMachine(withFlavorRef("flavor-2"))},
+		},
+		"RevertOtherFlavor": {
+			reason: "A resize to a flavor that is no longer desired should be reverted.",
+			cr:     virtualMachine(withFlavorRef("flavor-2")),
+			server: server("VERIFY_RESIZE", "flavor-3", 100),
+			want:   want{action: ucansdk.ActionRevertResize, cr: virtualMachine(withFlavorRef("flavor-2"))},
+		},
+		"RevertOnError": {
+			reason: "A resize should be reverted if the server fails.",
+			cr: virtualMachine(withFlavorRef("flavor-2"),
+				withResize(&v1alpha1.ResizeObservation{FromFlavorRef: "flavor-1", ToFlavorRef: "flavor-2", Phase: v1alpha1.ResizePhaseResizing})),
+			server: server("ERROR", "flavor-2", 0),
+			want: want{action: ucansdk.ActionRevertResize, cr: virtualMachine(withFlavorRef("flavor-2"),
+				withResize(&v1alpha1.ResizeObservation{FromFlavorRef: "flavor-1", ToFlavorRef: "flavor-2", Phase: v1alpha1.ResizePhaseResizing}))},
+		},
+		"Confirmed": {
+			reason: "A confirmed resize should be reported as complete.",
+			cr: virtualMachine(withFlavorRef("flavor-2"),
+				withResize(&v1alpha1.ResizeObservation{FromFlavorRef: "flavor-1", ToFlavorRef: "flavor-2", Phase: v1alpha1.ResizePhaseConfirming, Progress: 100})),
+			server: server("Running", "flavor-2", 0),
+			want: want{cr: virtualMachine(withFlavorRef("flavor-2"),
+				withResize(&v1alpha1.ResizeObservation{FromFlavorRef: "flavor-1", ToFlavorRef: "flavor-2", Phase: v1alpha1.ResizePhaseConfirmed}),
+				withConditions(v1alpha1.ResizeConfirmed("flavor-2")))},
+		},
+		"Reverted": {
+			reason: "A reverted resize should be reported and not retried.",
+			cr: virtualMachine(withFlavorRef("flavor-2"),
+				withResize(&v1alpha1.ResizeObservation{FromFlavorRef: "flavor-1", ToFlavorRef: "flavor-2", Phase: v1alpha1.ResizePhaseReverting})),
+			server: server("Running", "flavor-1", 0),
+			want: want{cr: virtualMachine(withFlavorRef("flavor-2"),
+				withResize(&v1alpha1.ResizeObservation{FromFlavorRef: "flavor-1", ToFlavorRef: "flavor-2", Phase: v1alpha1.ResizePhaseReverted}),
+				withConditions(v1alpha1.ResizeReverted("flavor-1", "flavor-2")))},
+		},
+		"RetryAfterRevert": {
+			reason: "A new flavor should be resized to even if the last resize was reverted.",
+			cr: virtualMachine(withFlavorRef("flavor-3"),
+				withResize(&v1alpha1.ResizeObservation{FromFlavorRef: "flavor-1", ToFlavorRef: "flavor-2", Phase: v1alpha1.ResizePhaseReverted})),
+			server: server("Running", "flavor-1", 0),
+			want: want{action: ucansdk.ActionResize, cr: virtualMachine(withFlavorRef("flavor-3"),
+				withResize(&v1alpha1.ResizeObservation{FromFlavorRef: "flavor-1", ToFlavorRef: "flavor-2", Phase: v1alpha1.ResizePhaseReverted}))},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			action := observeResize(tc.cr, tc.server)
+			if action != tc.want.action {
+				t.Errorf("\n%s\nobserveResize(...): want action %q, got %q", tc.reason, tc.want.action, action)
+			}
+			if diff := cmp.Diff(tc.want.cr, tc.cr, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\nobserveResize(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestResize(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		action  string
+		cr      *v1alpha1.VirtualMachine
+		wantReq string
+		want    *v1alpha1.VirtualMachine
+	}{
+		"Resize": {
+			reason:  "Resizing should request the desired flavor and record the resize.",
+			action:  ucansdk.ActionResize,
+			cr:      virtualMachine(withExternalName("vm-1"), withFlavorRef("flavor-2"), withAtProvider(v1alpha1.VirtualMachineObservation{FlavorRef: "flavor-1"})),
+			wantReq: `POST /v3/servers/vm-1/action {"resize":{"flavorRef":"flavor-2"}}`,
+			want: virtualMachine(withExternalName("vm-1"), withFlavorRef("flavor-2"),
+				withAtProvider(v1alpha1.VirtualMachineObservation{FlavorRef: "flavor-1", Resize: &v1alpha1.ResizeObservation{
+					FromFlavorRef: "flavor-1", ToFlavorRef: "flavor-2", Phase: v1alpha1.ResizePhaseResizing,
+				}}),
+				withConditions(v1alpha1.Resizing("flavor-1", "flavor-2"))),
+		},
+		"Confirm": {
+			reason:  "Confirming should send a confirmResize action.",
+			action:  ucansdk.ActionConfirmResize,
+			cr:      virtualMachine(withExternalName("vm-1"), withResize(&v1alpha1.ResizeObservation{FromFlavorRef: "flavor-1", ToFlavorRef: "flavor-2", Phase: v1alpha1.ResizePhaseResizing})),
+			wantReq: `POST /v3/servers/vm-1/action {"confirmResize":null}`,
+			want:    virtualMachine(withExternalName("vm-1"), withResize(&v1alpha1.ResizeObservation{FromFlavorRef: "flavor-1", ToFlavorRef: "flavor-2", Phase: v1alpha1.ResizePhaseConfirming})),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var req string
+			svc := newService(t, func(_ http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				req = r.Method + " " + r.URL.Path + " " + string(body)
+			})
+			e := external{service: svc, logger: logging.NewNopLogger()}
+			if err := e.resize(context.Background(), tc.cr, tc.action); err != nil {
+				t.Fatalf("\n%s\ne.resize(...): %v", tc.reason, err)
+			}
+			if req != tc.wantReq {
+				t.Errorf("\n%s\ne.resize(...): want request %s, got %s", tc.reason, tc.wantReq, req)
+			}
+			if diff := cmp.Diff(tc.want, tc.cr, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\ne.resize(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualmachine
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/provider-ucan/apis/osgalaxy/v1alpha1"
+)
+
+const (
+	errRenderUserData     = "cannot render user data template"
+	errParseTemplate      = "cannot parse template"
+	errExecuteTemplate    = "cannot execute template"
+	errResolveVariable    = "cannot resolve variable %q"
+	errNoVariableSource   = "no source is set"
+	errUnsupportedKind    = "unsupported kind %q"
+	errGetManaged         = "cannot get %s %s"
+	errNoConnectionSecret = "%s %s does not write its connection details to a secret"
+)
+
+// templateData is the data a UserDataTemplate is rendered with.
+type templateData struct {
+	Name        string
+	Labels      map[string]string
+	Annotations map[string]string
+	Values      map[string]string
+}
+
+// renderUserData resolves the variables of the user data template of cr and
+// renders it. It also returns the source of the user data: the template
+// rendered with the version of each variable in place of its value, which
+// changes with the user data without revealing any Secret it was read from.
+func (c *external) renderUserData(ctx context.Context, cr *v1alpha1.VirtualMachine) ([]byte, []byte, error) {
+	t := cr.Spec.ForProvider.UserDataTemplate
+
+	tmpl, err := template.New("userData").Option("missingkey=error").Parse(t.Template)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errParseTemplate)
+	}
+
+	data := templateData{
+		Name:        cr.GetName(),
+		Labels:      cr.GetLabels(),
+		Annotations: templateAnnotations(cr.GetAnnotations()),
+		Values:      make(map[string]string, len(t.Variables)),
+	}
+	versions := make(map[string]string, len(t.Variables))
+	for _, v := range t.Variables {
+		val, version, err := c.resolveVariable(ctx, v)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, errResolveVariable, v.Name)
+		}
+		data.Values[v.Name] = string(val)
+		versions[v.Name] = version
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, nil, errors.Wrap(err, errExecuteTemplate)
+	}
+	data.Values = versions
+	var src bytes.Buffer
+	if err := tmpl.Execute(&src, data); err != nil {
+		return nil, nil, errors.Wrap(err, errExecuteTemplate)
+	}
+	return buf.Bytes(), src.Bytes(), nil
+}
+
+// templateAnnotations returns the annotations a template is rendered with. The
+// annotations Crossplane and this provider set, e.g. the external name and the
+// hashes of the contents, change as the VirtualMachine is reconciled, so they
+// are left out to keep the rendered user data stable.
+func templateAnnotations(a map[string]string) map[string]string {
+	out := make(map[string]string, len(a))
+	for k, v := range a {
+		if strings.HasPrefix(k, "crossplane.io/") || strings.HasPrefix(k, "ucan.io/") {
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// resolveVariable returns the value of v and the version it was read from.
+func (c *external) resolveVariable(ctx context.Context, v v1alpha1.TemplateVariable) ([]byte, string, error) {
+	switch {
+	case v.SecretKeyRef != nil:
+		return c.secretKey(ctx, *v.SecretKeyRef)
+	case v.ConfigMapKeyRef != nil:
+		return c.configMapKey(ctx, *v.ConfigMapKeyRef)
+	case v.ConnectionDetailRef != nil:
+		return c.connectionDetail(ctx, *v.ConnectionDetailRef)
+	}
+	return nil, "", errors.New(errNoVariableSource)
+}
+
+// connectionDetail reads a connection detail from the connection secret of a
+// managed resource.
+func (c *external) connectionDetail(ctx context.Context, ref v1alpha1.ConnectionDetailSelector) ([]byte, string, error) {
+	var mg resource.Managed
+	switch ref.Kind {
+	case v1alpha1.VirtualMachineKind:
+		mg = &v1alpha1.VirtualMachine{}
+	case v1alpha1.VolumeKind:
+		mg = &v1alpha1.Volume{}
+	case v1alpha1.FloatingipKind:
+		mg = &v1alpha1.Floatingip{}
+	default:
+		return nil, "", errors.Errorf(errUnsupportedKind, ref.Kind)
+	}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: ref.Name}, mg); err != nil {
+		return nil, "", errors.Wrapf(err, errGetManaged, ref.Kind, ref.Name)
+	}
+	s := mg.GetWriteConnectionSecretToReference()
+	if s == nil {
+		return nil, "", errors.Errorf(errNoConnectionSecret, ref.Kind, ref.Name)
+	}
+	return c.secretKey(ctx, xpv1.SecretKeySelector{
+		SecretReference: xpv1.SecretReference{Name: s.Name, Namespace: s.Namespace},
+		Key:             ref.Key,
+	})
+}
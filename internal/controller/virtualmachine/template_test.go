@@ -0,0 +1,160 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualmachine
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-ucan/apis/osgalaxy/v1alpha1"
+)
+
+func withTemplate(t *v1alpha1.UserDataTemplate) vmModifier {
+	return func(cr *v1alpha1.VirtualMachine) { cr.Spec.ForProvider.UserDataTemplate = t }
+}
+
+func TestRenderUserData(t *testing.T) {
+	// The Floatingip writes its connection details to default/eip, which
+	// holds its address. Every other Secret holds a token. The UID of each
+	// Secret is its name.
+	get := func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+		switch o := obj.(type) {
+		case *v1alpha1.Floatingip:
+			o.SetWriteConnectionSecretToReference(&xpv1.SecretReference{Name: "eip", Namespace: "default"})
+		case *corev1.Secret:
+			o.SetUID(types.UID(key.Name))
+			o.SetResourceVersion("1")
+			if key.Name == "eip" {
+				o.Data = map[string][]byte{"address": []byte("203.0.113.5")}
+				return nil
+			}
+			o.Data = map[string][]byte{"token": []byte("s3cr3t")}
+		}
+		return nil
+	}
+
+	type want struct {
+		data   string
+		source string
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		cr     *v1alpha1.VirtualMachine
+		want   want
+	}{
+		"Render": {
+			reason: "The template should be rendered with the metadata of the VirtualMachine and its variables.",
+			cr: virtualMachine(withTemplate(&v1alpha1.UserDataTemplate{
+				Template: "#cloud-config\nhostname: {{ .Name }}-{{ .Labels.env }}\n# {{ .Values.token }} {{ .Values.address }}",
+				Variables: []v1alpha1.TemplateVariable{
+					{Name: "token", SecretKeyRef: &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Name: "vm", Namespace: "default"}, Key: "token"}},
+					{Name: "address", ConnectionDetailRef: &v1alpha1.ConnectionDetailSelector{Kind: v1alpha1.FloatingipKind, Name: "eip", Key: "address"}},
+				},
+			}), func(cr *v1alpha1.VirtualMachine) {
+				cr.SetName("web")
+				cr.SetLabels(map[string]string{"env": "prod"})
+			}),
+			want: want{
+				data:   "#cloud-config\nhostname: web-prod\n# s3cr3t 203.0.113.5",
+				source: "#cloud-config\nhostname: web-prod\n# vm/1/token eip/1/address",
+			},
+		},
+		"Annotations": {
+			reason: "The annotations set by Crossplane and the provider should be left out of the template data.",
+			cr: virtualMachine(withTemplate(&v1alpha1.UserDataTemplate{
+				Template: "{{ range $k, $v := .Annotations }}{{ $k }}={{ $v }};{{ end }}",
+			}), withExternalName("vm-1"), func(cr *v1alpha1.VirtualMachine) {
+				meta.AddAnnotations(cr, map[string]string{"team": "web", userDataHashAnnotationKey: "abc"})
+			}),
+			want: want{data: "team=web;", source: "team=web;"},
+		},
+		"MissingValue": {
+			reason: "Referring to a variable that is not set should return an error.",
+			cr:     virtualMachine(withTemplate(&v1alpha1.UserDataTemplate{Template: "{{ .Values.token }}"})),
+			want: want{err: errors.Wrap(errors.New(`template: userData:1:10: executing "userData" at <.Values.token>: map has no entry for key "token"`),
+				errExecuteTemplate)},
+		},
+		"NoSource": {
+			reason: "A variable without a source should return an error.",
+			cr: virtualMachine(withTemplate(&v1alpha1.UserDataTemplate{
+				Template:  "{{ .Values.token }}",
+				Variables: []v1alpha1.TemplateVariable{{Name: "token"}},
+			})),
+			want: want{err: errors.Wrap(errors.New(errNoVariableSource), `cannot resolve variable "token"`)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{kube: &test.MockClient{MockGet: get}, logger: logging.NewNopLogger()}
+			got, src, err := e.renderUserData(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.renderUserData(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.data, string(got)); diff != "" {
+				t.Errorf("\n%s\ne.renderUserData(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.source, string(src)); diff != "" {
+				t.Errorf("\n%s\ne.renderUserData(...): -want source, +got source:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreateRenderFailed(t *testing.T) {
+	svc := newService(t, func(_ http.ResponseWriter, r *http.Request) {
+		t.Errorf("e.Create(...): want no UCAN request, got %s %s", r.Method, r.URL.Path)
+	})
+	e := external{kube: &test.MockClient{}, service: svc, logger: logging.NewNopLogger()}
+	cr := virtualMachine(withTemplate(&v1alpha1.UserDataTemplate{Template: "{{ .Values.token"}))
+
+	if _, err := e.Create(context.Background(), cr); err == nil {
+		t.Fatal("e.Create(...): want error, got nil")
+	}
+	if c := cr.GetCondition(v1alpha1.TypeUserDataRendered); c.Reason != v1alpha1.ReasonRenderFailed {
+		t.Errorf("e.Create(...): want condition reason %s, got %+v", v1alpha1.ReasonRenderFailed, c)
+	}
+}
+
+func TestObserveRendered(t *testing.T) {
+	svc := newService(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(serverJSON))
+	})
+	e := external{kube: &test.MockClient{}, service: svc, logger: logging.NewNopLogger()}
+	cr := virtualMachine(withExternalName("vm-1"), withTemplate(&v1alpha1.UserDataTemplate{Template: "#cloud-config\nhostname: {{ .Name }}"}))
+
+	if _, err := e.Observe(context.Background(), cr); err != nil {
+		t.Fatalf("e.Observe(...): %v", err)
+	}
+	if diff := cmp.Diff(v1alpha1.UserDataRendered(), cr.GetCondition(v1alpha1.TypeUserDataRendered), test.EquateConditions()); diff != "" {
+		t.Errorf("e.Observe(...): the rendered template should be reported: -want condition, +got condition:\n%s", diff)
+	}
+}
@@ -3,26 +3,30 @@ package virtualmachine
 import (
 	"context"
 	"encoding/json"
-	"fmt"
-	"net/http"
+	"maps"
+	"slices"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/connection"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/provider-ucan/pkg/ucansdk"
 	"github.com/pkg/errors"
-	"k8s.io/apimachinery/pkg/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	"github.com/crossplane/provider-ucan/apis/osgalaxy/v1alpha1"
 	apisv1alpha1 "github.com/crossplane/provider-ucan/apis/v1alpha1"
+	"github.com/crossplane/provider-ucan/internal/clients"
+	"github.com/crossplane/provider-ucan/internal/drift"
+	"github.com/crossplane/provider-ucan/internal/externalname"
 	"github.com/crossplane/provider-ucan/internal/features"
 	"github.com/crossplane/provider-ucan/pkg/httpclient"
 )
@@ -30,30 +34,29 @@ import (
 const (
 	errNotVirtualMachine = "managed resource is not a VirtualMachine custom resource"
 	errTrackPCUsage      = "cannot track ProviderConfig usage"
-	errGetPC             = "cannot get ProviderConfig"
-	errGetCreds          = "cannot get credentials"
 	errNewClient         = "cannot create new Service"
 
+	// vmUUIDAnnotationKey held the UCAN ID before the external-name annotation
+	// was adopted. It is migrated by the LegacyAnnotationMigrator.
 	vmUUIDAnnotationKey = "ucan.io/virtualmachine-uuid"
+
+	// addressTypeFloating is the OS-EXT-IPS:type of a floating IP address.
+	addressTypeFloating = "floating"
+)
+
+// Keys of the connection details of a VirtualMachine.
+const (
+	keyPrivateIPV4   = "privateIpV4"
+	keyPublicIPV4    = "publicIpV4"
+	keyPrivateIPV6   = "privateIpV6"
+	keyPublicIPV6    = "publicIpV6"
+	keyAdminPassword = "adminPassword"
 )
 
 type UcanClient struct {
-	HttpClient *httpclient.HttpClient
+	*ucansdk.Client
 }
 
-var (
-	newUcanClient = func(credentials []byte) (*UcanClient, error) {
-		var signCertificate httpclient.SignCertificate
-		if err := json.Unmarshal(credentials, &signCertificate); err != nil {
-			fmt.Println("*************cannot get credentials*************")
-			return nil, err
-		}
-		cli := httpclient.NewHttpClient(signCertificate)
-		cli.SetHeader("Content-Type", "application/json")
-		return &UcanClient{HttpClient: cli}, nil
-	}
-)
-
 // Setup adds a controller that reconciles VirtualMachine managed resources.
 func Setup(mgr ctrl.Manager, o controller.Options) error {
 	name := managed.ControllerName(v1alpha1.VirtualMachineGroupKind)
@@ -63,16 +66,24 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
 	}
 
-	r := managed.NewReconciler(mgr,
-		resource.ManagedKind(v1alpha1.VirtualMachineGroupVersionKind),
+	log := o.Logger.WithValues("controller", name)
+	opts := []managed.ReconcilerOption{
 		managed.WithExternalConnecter(&connector{
-			kube:         mgr.GetClient(),
-			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
-			newServiceFn: newUcanClient}),
-		managed.WithLogger(o.Logger.WithValues("controller", name)),
+			kube:    mgr.GetClient(),
+			usage:   resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			clients: clients.DefaultCache(),
+			logger:  log}),
+		managed.WithInitializers(externalname.NewLegacyAnnotationMigrator(mgr.GetClient(), vmUUIDAnnotationKey)),
+		managed.WithLogger(log),
 		managed.WithPollInterval(o.PollInterval),
 		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-		managed.WithConnectionPublishers(cps...))
+		managed.WithConnectionPublishers(cps...),
+	}
+	if o.Features.Enabled(features.EnableAlphaManagementPolicies) {
+		opts = append(opts, managed.WithManagementPolicies())
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1alpha1.VirtualMachineGroupVersionKind), opts...)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
@@ -85,9 +96,10 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 // A connector is expected to produce an ExternalClient when its Connect method
 // is called.
 type connector struct {
-	kube         client.Client
-	usage        resource.Tracker
-	newServiceFn func(creds []byte) (*UcanClient, error)
+	kube    client.Client
+	usage   resource.Tracker
+	clients *clients.Cache
+	logger  logging.Logger
 }
 
 func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
@@ -100,30 +112,28 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errTrackPCUsage)
 	}
 
-	pc := &apisv1alpha1.ProviderConfig{}
-	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.GetProviderConfigReference().Name}, pc); err != nil {
-		return nil, errors.Wrap(err, errGetPC)
-	}
-
-	cd := pc.Spec.Credentials
-	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.kube, cd.CommonCredentialSelectors)
-	if err != nil {
-		return nil, errors.Wrap(err, errGetCreds)
-	}
-
-	svc, err := c.newServiceFn(data)
+	svc, err := c.clients.Get(ctx, c.kube, mg)
 	if err != nil {
 		return nil, errors.Wrap(err, errNewClient)
 	}
 
-	zl := zap.New(zap.UseDevMode(true))
-	log := logging.NewLogrLogger(zl.WithName("provider-vm"))
-	return &external{service: svc, logger: log}, nil
+	log := c.logger.WithValues("name", cr.GetName(), "project", cr.Spec.ForProvider.ProjectId, "externalID", meta.GetExternalName(cr))
+	return &external{kube: c.kube, service: &UcanClient{Client: svc.WithOptions(httpclient.WithLogger(log))}, logger: log}, nil
 }
 
 type external struct {
+	kube    client.Client
 	service *UcanClient
 	logger  logging.Logger
+
+	// diff is the drift found by the last call to Observe. removedMetadata
+	// are the metadata keys to delete from the server. resizeAction and
+	// powerAction are the server actions needed to converge on the desired
+	// flavor and power state.
+	diff            drift.Diff
+	removedMetadata []string
+	resizeAction    string
+	powerAction     string
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -132,45 +142,76 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.New(errNotVirtualMachine)
 	}
 
-	uuid, ok := cr.GetAnnotations()[vmUUIDAnnotationKey]
-	if !ok {
-		c.logger.Info("Resource Status", "msg", "uuid not found")
+	uuid := meta.GetExternalName(cr)
+	if uuid == "" {
+		c.logger.Debug("External resource has no ID")
 		return managed.ExternalObservation{
 			ResourceExists: false,
 		}, nil
 	}
 
-	vm, code, err := ucansdk.GetVm(c.service.HttpClient, uuid)
+	vm, err := ucansdk.GetVm(ctx, c.service.Client, uuid)
+	if ucansdk.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
 	if err != nil {
-		c.logger.Info("get Resource err", "msg", err)
+		c.logger.Debug("Cannot get virtual machine", "error", err)
 		return managed.ExternalObservation{}, errors.Wrap(err, "cannot get virtual machine")
 	}
-	if code >= http.StatusBadRequest && code != http.StatusNotFound {
-		c.logger.Info("get Resource err", "code", code, "body", string(vm))
-		return managed.ExternalObservation{}, errors.New("cannot get virtual machine")
-	}
-
-	resourceExists := code != http.StatusNotFound
 
 	var response ucansdk.ServerResp
 	if err = json.Unmarshal(vm, &response); err != nil {
-		c.logger.Info("unmarshal err get Resource", "msg", err)
+		c.logger.Debug("Cannot unmarshal virtual machine", "error", err)
 		return managed.ExternalObservation{}, errors.Wrap(err, "cannot unmarshal virtual machine")
 	}
-	c.logger.Info("unmarshal Resource", "id", response.Server.ID, "name", response.Server.Name, "status", response.Server.Status)
-	cr.Status.AtProvider = v1alpha1.VirtualMachineObservation{
-		Status: response.Server.Status,
+	c.logger.Debug("Observed virtual machine", "status", response.Server.Status)
+	// The hashes, the applied metadata keys and the progress of a resize are
+	// recorded by the provider, and cannot be observed.
+	prev := cr.Status.AtProvider
+	cr.Status.AtProvider = generateObservation(response)
+	cr.Status.AtProvider.Resize = prev.Resize
+	cr.Status.AtProvider.MetadataKeys = prev.MetadataKeys
+	cr.Status.AtProvider.UserDataHash = cr.GetAnnotations()[userDataHashAnnotationKey]
+	cr.Status.AtProvider.PersonalityHash = cr.GetAnnotations()[personalityHashAnnotationKey]
+	desiredPower := cr.Spec.ForProvider.PowerState
+	if desiredPower == "" {
+		desiredPower = v1alpha1.PowerStateRunning
 	}
-	if resourceExists && response.Server.Status == "Running" {
+	if cr.Status.AtProvider.PowerState == desiredPower {
 		// 将状态置为可用
 		cr.SetConditions(xpv1.Available())
 	}
 
-	return managed.ExternalObservation{
-		ResourceExists:    resourceExists,
-		ResourceUpToDate:  true,
-		ConnectionDetails: managed.ConnectionDetails{},
-	}, nil
+	c.diff = generateDiff(cr, response)
+	c.removedMetadata = removedMetadata(cr, response.Server.Metadata)
+	if !c.diff.Has("metadata") {
+		cr.Status.AtProvider.MetadataKeys = metadataKeys(cr)
+	}
+	if err := c.diffContents(ctx, cr, &c.diff); err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	if len(c.diff) > 0 {
+		c.logger.Debug("Observed drift", "diff", c.diff.String())
+	}
+	c.resizeAction = observeResize(cr, response)
+	if c.resizeAction != "" {
+		c.logger.Debug("Observed pending resize", "action", c.resizeAction, "flavor", response.Server.Flavor.ID)
+	}
+	c.powerAction = ""
+	if c.resizeAction == "" {
+		c.powerAction = powerAction(cr.Status.AtProvider.PowerState, cr.Spec.ForProvider.PowerState)
+	}
+	if c.powerAction != "" {
+		c.logger.Debug("Observed pending power state change", "action", c.powerAction, "powerState", cr.Status.AtProvider.PowerState)
+	}
+
+	o, err := drift.Observation(cr, c.diff)
+	if err != nil {
+		return o, err
+	}
+	o.ResourceUpToDate = o.ResourceUpToDate && c.resizeAction == "" && c.powerAction == ""
+	o.ConnectionDetails = connectionDetails(cr.Status.AtProvider)
+	return o, nil
 }
 
 func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
@@ -179,58 +220,38 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalCreation{}, errors.New(errNotVirtualMachine)
 	}
 
-	req := ucansdk.CreateServerReq{
-		Name:               cr.Spec.ForProvider.Name,
-		CellID:             cr.Spec.ForProvider.CellId,
-		ProjectID:          cr.Spec.ForProvider.ProjectId,
-		ImageRef:           cr.Spec.ForProvider.ImageRef,
-		FlavorRef:          cr.Spec.ForProvider.FlavorRef,
-		BlockDeviceMapping: make([]ucansdk.BlockDeviceMapping, 0, len(cr.Spec.ForProvider.BlockDeviceMapping)),
-	}
-	for _, v := range cr.Spec.ForProvider.BlockDeviceMapping {
-		req.BlockDeviceMapping = append(req.BlockDeviceMapping, ucansdk.BlockDeviceMapping{
-			BootIndex:           int(v.BootIndex),
-			SourceType:          v.SourceType,
-			DestinationType:     v.DestinationType,
-			VolumeSize:          int(v.VolumeSize),
-			VolumeType:          v.VolumeType,
-			DeleteOnTermination: v.DeleteOnTermination,
-		})
+	cs, err := c.resolveContents(ctx, cr)
+	if err != nil {
+		return managed.ExternalCreation{}, err
 	}
-	reqData, err := json.Marshal(req)
+	reqData, err := json.Marshal(generateCreateRequest(cr, cs))
 	if err != nil {
-		c.logger.Info("Marshal err create Resource", "msg", err)
+		c.logger.Debug("Cannot marshal virtual machine request", "error", err)
 		return managed.ExternalCreation{}, errors.Wrap(err, "cannot create virtual machine")
 	}
-	c.logger.Info("create Resource", "req", string(reqData))
-	vm, code, err := ucansdk.CreateVm(c.service.HttpClient, reqData)
+	vm, err := ucansdk.CreateVm(ctx, c.service.Client, reqData)
 	if err != nil {
-		c.logger.Info("create Resource err", "msg", err)
+		c.logger.Debug("Cannot create virtual machine", "error", err)
 		return managed.ExternalCreation{}, errors.Wrap(err, "cannot create virtual machine")
 	}
-	if code >= http.StatusBadRequest {
-		c.logger.Info("create Resource err", "code", code, "body", string(vm))
-		return managed.ExternalCreation{}, errors.New("cannot create virtual machine")
-	}
 
 	var response ucansdk.ServerResp
 	if err = json.Unmarshal(vm, &response); err != nil {
-		c.logger.Info("unmarshal err create Resource", "msg", err)
+		c.logger.Debug("Cannot unmarshal virtual machine", "error", err)
 		return managed.ExternalCreation{}, errors.Wrap(err, "cannot unmarshal virtual machine")
 	}
-	c.logger.Info("unmarshal Resource", "id", response.Server.ID, "name", response.Server.Name)
-
-	// 将虚拟机的uuid存入标签中
-	mg.SetAnnotations(map[string]string{
-		vmUUIDAnnotationKey: response.Server.ID,
-	})
-	return managed.ExternalCreation{
-		// Optionally return any details that may be required to connect to the
-		// external resource. These will be stored as the connection secret.
-		ConnectionDetails: managed.ConnectionDetails{
-			"observableField": []byte(response.Server.ID),
-		},
-	}, nil
+	c.logger.Debug("Created virtual machine", "externalID", response.Server.ID)
+
+	meta.SetExternalName(cr, response.Server.ID)
+	setContentHashes(cr, cs)
+
+	// UCAN only returns a generated admin password when the server is
+	// created, so it must be published now or never.
+	cd := managed.ConnectionDetails{}
+	if response.Server.AdminPass != "" {
+		cd[keyAdminPassword] = []byte(response.Server.AdminPass)
+	}
+	return managed.ExternalCreation{ConnectionDetails: cd}, nil
 }
 
 func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
@@ -239,13 +260,44 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, errors.New(errNotVirtualMachine)
 	}
 
-	fmt.Printf("Updating: name:%s\tfinalizers: %+v\n", cr.Name, cr.Finalizers)
+	uuid := meta.GetExternalName(cr)
+	diff := c.diff.Updatable()
+	c.logger.Debug("Updating virtual machine", "diff", diff.String())
+
+	if diff.Has("name") || diff.Has("description") {
+		req := ucansdk.UpdateServerReq{Server: ucansdk.UpdateServerParams{
+			Name:        cr.Spec.ForProvider.Name,
+			Description: cr.Spec.ForProvider.Description,
+		}}
+		reqData, err := json.Marshal(req)
+		if err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, "cannot update virtual machine")
+		}
+		if _, err := ucansdk.UpdateVm(ctx, c.service.Client, uuid, reqData); err != nil {
+			c.logger.Debug("Cannot update virtual machine", "error", err)
+			return managed.ExternalUpdate{}, errors.Wrap(err, "cannot update virtual machine")
+		}
+	}
+
+	if diff.Has("metadata") {
+		if err := c.updateMetadata(ctx, cr, c.removedMetadata); err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+	}
+
+	if c.resizeAction != "" {
+		if err := c.resize(ctx, cr, c.resizeAction); err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+	}
+	if c.powerAction != "" {
+		if err := c.power(ctx, cr, c.powerAction); err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+	}
+	c.logger.Debug("Updated virtual machine")
 
-	return managed.ExternalUpdate{
-		// Optionally return any details that may be required to connect to the
-		// external resource. These will be stored as the connection secret.
-		ConnectionDetails: managed.ConnectionDetails{},
-	}, nil
+	return managed.ExternalUpdate{}, nil
 }
 
 func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
@@ -254,21 +306,17 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalDelete{}, errors.New(errNotVirtualMachine)
 	}
 
-	uuid, ok := cr.GetAnnotations()[vmUUIDAnnotationKey]
-	if !ok {
-		c.logger.Info("volume Resource Status", "name", cr.Name, "msg", "uuid not found")
+	uuid := meta.GetExternalName(cr)
+	if uuid == "" {
+		c.logger.Debug("External resource has no ID")
 		return managed.ExternalDelete{}, nil
 	}
-	body, code, err := ucansdk.DelVm(c.service.HttpClient, uuid)
-	if err != nil {
-		c.logger.Info("delete Resource err", "name", cr.Name, "msg", err)
+	_, err := ucansdk.DelVm(ctx, c.service.Client, uuid)
+	if err != nil && !ucansdk.IsNotFound(err) {
+		c.logger.Debug("Cannot delete virtual machine", "error", err)
 		return managed.ExternalDelete{}, errors.Wrap(err, "cannot delete virtual machine")
 	}
-	if code != http.StatusNoContent {
-		c.logger.Info("delete Resource err", "name", cr.Name, "code", code, "body", string(body))
-		return managed.ExternalDelete{}, errors.New("cannot delete virtual machine")
-	}
-	c.logger.Info("delete Resource", "name", cr.Name, "code", code)
+	c.logger.Debug("Deleted virtual machine")
 
 	return managed.ExternalDelete{}, nil
 }
@@ -277,6 +325,171 @@ func (c *external) Disconnect(ctx context.Context) error {
 	return nil
 }
 
-// func (c *external) isUpToDate(cr *v1alpha1.VirtualMachine, externalResource map[string]any) (bool, string) {
-//	return true, ""
-// }
+// generateCreateRequest returns the request that creates the UCAN server of a
+// VirtualMachine with the supplied contents. PowerState is not part of the
+// request; it is converged by Update once the server exists.
+func generateCreateRequest(cr *v1alpha1.VirtualMachine, cs contents) ucansdk.CreateServerReq {
+	p := cr.Spec.ForProvider
+	req := ucansdk.CreateServerReq{
+		Name:               p.Name,
+		Description:        p.Description,
+		CellID:             p.CellId,
+		ProjectID:          p.ProjectId,
+		AccessIPv4:         p.AccessIPV4,
+		AccessIPv6:         p.AccessIPV6,
+		ImageRef:           p.ImageRef,
+		FlavorRef:          p.FlavorRef,
+		AvailabilityZone:   p.AvailabilityZone,
+		Metadata:           p.Metadata,
+		UserData:           cs.userData,
+		Personality:        cs.personality,
+		BlockDeviceMapping: make([]ucansdk.BlockDeviceMapping, 0, len(p.BlockDeviceMapping)),
+	}
+	for _, v := range p.BlockDeviceMapping {
+		req.BlockDeviceMapping = append(req.BlockDeviceMapping, ucansdk.BlockDeviceMapping{
+			BootIndex:           int(v.BootIndex),
+			SourceType:          v.SourceType,
+			DestinationType:     v.DestinationType,
+			UUID:                v.UUID,
+			DeviceName:          v.DeviceName,
+			VolumeSize:          int(v.VolumeSize),
+			VolumeType:          v.VolumeType,
+			DeleteOnTermination: v.DeleteOnTermination,
+		})
+	}
+	for _, sg := range p.SecurityGroups {
+		req.SecurityGroups = append(req.SecurityGroups, ucansdk.SecurityGroup{Name: sg.Name})
+	}
+	return req
+}
+
+// generateObservation projects the UCAN server onto the observable fields of a
+// VirtualMachine.
+func generateObservation(resp ucansdk.ServerResp) v1alpha1.VirtualMachineObservation {
+	s := resp.Server
+	o := v1alpha1.VirtualMachineObservation{
+		ID:               s.ID,
+		Name:             s.Name,
+		Description:      s.Description,
+		ProjectId:        s.TenantID,
+		UserId:           s.UserID,
+		AccessIPV4:       s.AccessIPv4,
+		AccessIPV6:       s.AccessIPv6,
+		ImageRef:         s.Image.ID,
+		FlavorRef:        s.Flavor.ID,
+		AvailabilityZone: s.PinnedAZ,
+		Metadata:         s.Metadata,
+		Tags:             s.Tags,
+		Status:           s.Status,
+		HostID:           s.HostID,
+		KeyName:          ptr.Deref(s.KeyName, ""),
+		Locked:           s.Locked,
+		LockedReason:     s.LockedReason,
+		Progress:         int64(s.Progress),
+		PowerState:       powerState(s.Status),
+	}
+	if !s.Created.IsZero() {
+		o.Created = &metav1.Time{Time: s.Created}
+	}
+	if !s.Updated.IsZero() {
+		o.Updated = &metav1.Time{Time: s.Updated}
+	}
+	if s.Flavor.ID != "" || s.Flavor.Name != "" {
+		o.Flavor = &v1alpha1.FlavorObservation{
+			ID:        s.Flavor.ID,
+			Name:      s.Flavor.Name,
+			VCPUs:     int64(s.Flavor.VCPUs),
+			RAM:       int64(s.Flavor.RAM),
+			Disk:      int64(s.Flavor.Disk),
+			Swap:      int64(s.Flavor.Swap),
+			Ephemeral: int64(s.Flavor.OSFlavorExtDataEphemeral),
+		}
+	}
+	for _, sg := range s.SecurityGroups {
+		o.SecurityGroups = append(o.SecurityGroups, sg.Name)
+	}
+	for _, v := range s.VolumesAttached {
+		o.VolumesAttached = append(o.VolumesAttached, v1alpha1.AttachedVolumeObservation{ID: v.ID, DeleteOnTermination: v.DeleteOnTermination})
+	}
+
+	// Addresses are keyed by network name. Sort the networks so that the
+	// status, and the first address of each type, are stable.
+	for _, network := range slices.Sorted(maps.Keys(s.Addresses)) {
+		for _, a := range s.Addresses[network] {
+			o.Addresses = append(o.Addresses, v1alpha1.AddressObservation{
+				Network: network,
+				Addr:    a.Addr,
+				Version: int64(a.Version),
+				Type:    a.IPType,
+				MACAddr: a.MACAddr,
+			})
+			if a.Version != 4 {
+				continue
+			}
+			switch {
+			case a.IPType == addressTypeFloating && o.PublicIPV4 == "":
+				o.PublicIPV4 = a.Addr
+			case a.IPType != addressTypeFloating && o.PrivateIPV4 == "":
+				o.PrivateIPV4 = a.Addr
+			}
+		}
+	}
+	return o
+}
+
+// connectionDetails returns the first private and public IPv4 and IPv6
+// addresses of a VirtualMachine, if any.
+func connectionDetails(o v1alpha1.VirtualMachineObservation) managed.ConnectionDetails {
+	cd := managed.ConnectionDetails{}
+	set := func(key, addr string) {
+		if _, ok := cd[key]; !ok && addr != "" {
+			cd[key] = []byte(addr)
+		}
+	}
+	set(keyPrivateIPV4, o.PrivateIPV4)
+	set(keyPublicIPV4, o.PublicIPV4)
+	for _, a := range o.Addresses {
+		if a.Version != 6 {
+			continue
+		}
+		if a.Type == addressTypeFloating {
+			set(keyPublicIPV6, a.Addr)
+			continue
+		}
+		set(keyPrivateIPV6, a.Addr)
+	}
+	return cd
+}
+
+// generateDiff compares the parameters of a VirtualMachine with the UCAN
+// server. Flavor changes are handled by observeResize.
+func generateDiff(cr *v1alpha1.VirtualMachine, resp ucansdk.ServerResp) drift.Diff {
+	p := cr.Spec.ForProvider
+	s := resp.Server
+	sgs := make([]string, 0, len(p.SecurityGroups))
+	for _, sg := range p.SecurityGroups {
+		sgs = append(sgs, sg.Name)
+	}
+	observedSgs := make([]string, 0, len(s.SecurityGroups))
+	for _, sg := range s.SecurityGroups {
+		observedSgs = append(observedSgs, sg.Name)
+	}
+
+	var d drift.Diff
+	drift.Compare(&d, "name", p.Name, s.Name, drift.Updatable)
+	drift.Compare(&d, "description", p.Description, s.Description, drift.Updatable)
+	drift.CompareMap(&d, "metadata", p.Metadata, s.Metadata, drift.Updatable)
+	if !d.Has("metadata") && len(removedMetadata(cr, s.Metadata)) > 0 {
+		d = append(d, drift.Field{Path: "metadata", Desired: p.Metadata, Observed: s.Metadata, Mode: drift.Updatable})
+	}
+	drift.Compare(&d, "projectId", p.ProjectId, s.TenantID, drift.Immutable)
+	// A server booted from a volume reports no image.
+	if s.Image.ID != "" {
+		drift.Compare(&d, "imageRef", p.ImageRef, s.Image.ID, drift.Immutable)
+	}
+	drift.Compare(&d, "availabilityZone", p.AvailabilityZone, s.PinnedAZ, drift.Immutable)
+	drift.Compare(&d, "accessIpV4", p.AccessIPV4, s.AccessIPv4, drift.Immutable)
+	drift.Compare(&d, "accessIpV6", p.AccessIPV6, s.AccessIPv6, drift.Immutable)
+	drift.CompareSet(&d, "securityGroups", sgs, observedSgs, drift.Immutable)
+	return d
+}
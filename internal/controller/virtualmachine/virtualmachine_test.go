@@ -17,14 +17,38 @@ limitations under the License.
 package virtualmachine
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-ucan/apis/osgalaxy/v1alpha1"
+	"github.com/crossplane/provider-ucan/internal/drift"
+	"github.com/crossplane/provider-ucan/pkg/httpclient"
+	"github.com/crossplane/provider-ucan/pkg/ucansdk"
 )
 
 // Unlike many Kubernetes projects Crossplane does not use third party testing
@@ -35,40 +59,561 @@ import (
 // https://github.com/golang/go/wiki/TestComments
 // https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
 
-func TestObserve(t *testing.T) {
-	type fields struct {
-		service *UcanClient
+var update = flag.Bool("update", false, "update golden files in testdata")
+
+const serverJSON = `{"server": {
+	"id": "vm-1",
+	"name": "web",
+	"description": "imported",
+	"tenant_id": "project-1",
+	"user_id": "user-1",
+	"accessIPv4": "10.0.0.5",
+	"image": {"id": "image-1"},
+	"flavor": {"id": "flavor-1", "name": "m1.small", "vcpus": 1, "ram": 2048, "disk": 20},
+	"pinned_availability_zone": "az-1",
+	"metadata": {"team": "web"},
+	"security_groups": [{"name": "default"}],
+	"status": "Running",
+	"hostId": "host-1",
+	"locked": true,
+	"locked_reason": "maintenance",
+	"created": "2025-01-02T03:04:05Z",
+	"addresses": {
+		"public": [{"addr": "2001:db8::5", "version": 6, "OS-EXT-IPS:type": "fixed"}],
+		"private": [
+			{"addr": "10.0.0.5", "version": 4, "OS-EXT-IPS:type": "fixed", "OS-EXT-IPS-MAC:mac_addr": "fa:16:3e:00:00:01"},
+			{"addr": "203.0.113.5", "version": 4, "OS-EXT-IPS:type": "floating", "OS-EXT-IPS-MAC:mac_addr": "fa:16:3e:00:00:01"}
+		]
+	},
+	"os-extended-volumes:volumes_attached": [{"id": "vol-1", "delete_on_termination": true}]
+}}`
+
+var serverObservation = v1alpha1.VirtualMachineObservation{
+	ID:               "vm-1",
+	Name:             "web",
+	Description:      "imported",
+	ProjectId:        "project-1",
+	UserId:           "user-1",
+	AccessIPV4:       "10.0.0.5",
+	ImageRef:         "image-1",
+	FlavorRef:        "flavor-1",
+	AvailabilityZone: "az-1",
+	Metadata:         map[string]string{"team": "web"},
+	SecurityGroups:   []string{"default"},
+	Status:           "Running",
+	HostID:           "host-1",
+	Locked:           true,
+	LockedReason:     "maintenance",
+	Created:          &metav1.Time{Time: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)},
+	PrivateIPV4:      "10.0.0.5",
+	PublicIPV4:       "203.0.113.5",
+	Addresses: []v1alpha1.AddressObservation{
+		{Network: "private", Addr: "10.0.0.5", Version: 4, Type: "fixed", MACAddr: "fa:16:3e:00:00:01"},
+		{Network: "private", Addr: "203.0.113.5", Version: 4, Type: "floating", MACAddr: "fa:16:3e:00:00:01"},
+		{Network: "public", Addr: "2001:db8::5", Version: 6, Type: "fixed"},
+	},
+	Flavor:          &v1alpha1.FlavorObservation{ID: "flavor-1", Name: "m1.small", VCPUs: 1, RAM: 2048, Disk: 20},
+	VolumesAttached: []v1alpha1.AttachedVolumeObservation{{ID: "vol-1", DeleteOnTermination: true}},
+	PowerState:      "Running",
+}
+
+var serverConnectionDetails = managed.ConnectionDetails{
+	"privateIpV4": []byte("10.0.0.5"),
+	"publicIpV4":  []byte("203.0.113.5"),
+	"privateIpV6": []byte("2001:db8::5"),
+}
+
+type vmModifier func(*v1alpha1.VirtualMachine)
+
+func withExternalName(n string) vmModifier {
+	return func(cr *v1alpha1.VirtualMachine) { meta.SetExternalName(cr, n) }
+}
+
+func withParameters(p v1alpha1.VirtualMachineParameters) vmModifier {
+	return func(cr *v1alpha1.VirtualMachine) { cr.Spec.ForProvider = p }
+}
+
+func withManagementPolicies(p ...xpv1.ManagementAction) vmModifier {
+	return func(cr *v1alpha1.VirtualMachine) { cr.SetManagementPolicies(p) }
+}
+
+func withAtProvider(o v1alpha1.VirtualMachineObservation) vmModifier {
+	return func(cr *v1alpha1.VirtualMachine) { cr.Status.AtProvider = o }
+}
+
+func withMetadataKeys(k ...string) vmModifier {
+	return func(cr *v1alpha1.VirtualMachine) { cr.Status.AtProvider.MetadataKeys = k }
+}
+
+func withConditions(c ...xpv1.Condition) vmModifier {
+	return func(cr *v1alpha1.VirtualMachine) { cr.SetConditions(c...) }
+}
+
+func virtualMachine(m ...vmModifier) *v1alpha1.VirtualMachine {
+	cr := &v1alpha1.VirtualMachine{}
+	for _, f := range m {
+		f(cr)
 	}
+	return cr
+}
+
+// newService returns a UcanClient whose endpoints are served by h.
+func newService(t *testing.T, h http.HandlerFunc) *UcanClient {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	ep := ucansdk.Endpoint{BaseURL: srv.URL}
+	cli := httpclient.NewHttpClient(httpclient.SignCertificate{AccessKeyID: "ak", SecretAccessKey: "sk"}, httpclient.WithRetryPolicy(httpclient.RetryPolicy{MaxAttempts: 1}))
+	return &UcanClient{Client: ucansdk.NewClient(cli, ucansdk.Endpoints{VirtualMachine: ep, Volume: ep, Network: ep})}
+}
 
+func TestObserve(t *testing.T) {
 	type args struct {
-		ctx context.Context
-		mg  resource.Managed
+		mg resource.Managed
 	}
 
 	type want struct {
+		mg  resource.Managed
 		o   managed.ExternalObservation
 		err error
 	}
 
 	cases := map[string]struct {
-		reason string
-		fields fields
-		args   args
-		want   want
+		reason  string
+		handler http.HandlerFunc
+		args    args
+		want    want
 	}{
-		// TODO: Add test cases.
+		"NoExternalName": {
+			reason: "A VirtualMachine without an external name should not exist.",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			args: args{mg: virtualMachine()},
+			want: want{
+				mg: virtualMachine(),
+				o:  managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"NotFound": {
+			reason: "A VirtualMachine that UCAN cannot find should not exist.",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			args: args{mg: virtualMachine(withExternalName("vm-1"))},
+			want: want{
+				mg: virtualMachine(withExternalName("vm-1")),
+				o:  managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"GetError": {
+			reason: "Errors getting the VirtualMachine should be returned.",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusForbidden)
+			},
+			args: args{mg: virtualMachine(withExternalName("vm-1"))},
+			want: want{
+				mg:  virtualMachine(withExternalName("vm-1")),
+				err: errors.Wrap(&ucansdk.APIError{StatusCode: http.StatusForbidden}, "cannot get virtual machine"),
+			},
+		},
+		"ObserveOnlyImport": {
+			reason: "An imported VirtualMachine should have its remote state reported in status.atProvider.",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet || r.URL.Path != "/v3/servers/vm-1" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				_, _ = w.Write([]byte(serverJSON))
+			},
+			args: args{mg: virtualMachine(withExternalName("vm-1"), withManagementPolicies(xpv1.ManagementActionObserve))},
+			want: want{
+				mg: virtualMachine(
+					withExternalName("vm-1"),
+					withManagementPolicies(xpv1.ManagementActionObserve),
+					withAtProvider(serverObservation),
+					withConditions(xpv1.Available()),
+				),
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ConnectionDetails: serverConnectionDetails,
+					ResourceUpToDate:  true,
+				},
+			},
+		},
+		"UpToDate": {
+			reason: "A VirtualMachine whose parameters match the server should be up to date.",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(serverJSON))
+			},
+			args: args{mg: virtualMachine(withExternalName("vm-1"), withParameters(v1alpha1.VirtualMachineParameters{
+				Name:           "web",
+				ProjectId:      "project-1",
+				ImageRef:       "image-1",
+				FlavorRef:      "flavor-1",
+				Metadata:       map[string]string{"team": "web"},
+				SecurityGroups: []v1alpha1.SecurityGroupParameters{{Name: "default"}},
+			}))},
+			want: want{
+				mg: virtualMachine(withExternalName("vm-1"), withParameters(v1alpha1.VirtualMachineParameters{
+					Name:           "web",
+					ProjectId:      "project-1",
+					ImageRef:       "image-1",
+					FlavorRef:      "flavor-1",
+					Metadata:       map[string]string{"team": "web"},
+					SecurityGroups: []v1alpha1.SecurityGroupParameters{{Name: "default"}},
+				}), withAtProvider(serverObservation), withMetadataKeys("team"), withConditions(xpv1.Available())),
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ConnectionDetails: serverConnectionDetails,
+					ResourceUpToDate:  true,
+				},
+			},
+		},
+		"VolumeBacked": {
+			reason: "A VirtualMachine booted from a volume reports an empty image, which should not drift from its imageRef.",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(strings.Replace(serverJSON, `{"id": "image-1"}`, `""`, 1)))
+			},
+			args: args{mg: virtualMachine(withExternalName("vm-1"), withParameters(v1alpha1.VirtualMachineParameters{
+				Name:     "web",
+				ImageRef: "image-1",
+			}))},
+			want: want{
+				mg: virtualMachine(withExternalName("vm-1"), withParameters(v1alpha1.VirtualMachineParameters{
+					Name:     "web",
+					ImageRef: "image-1",
+				}), withAtProvider(func() v1alpha1.VirtualMachineObservation {
+					o := serverObservation
+					o.ImageRef = ""
+					return o
+				}()), withConditions(xpv1.Available())),
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ConnectionDetails: serverConnectionDetails,
+					ResourceUpToDate:  true,
+				},
+			},
+		},
+		"UpdatableDrift": {
+			reason: "A VirtualMachine whose updatable parameters differ from the server should need an update.",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(serverJSON))
+			},
+			args: args{mg: virtualMachine(withExternalName("vm-1"), withParameters(v1alpha1.VirtualMachineParameters{
+				Name:     "api",
+				Metadata: map[string]string{"team": "api"},
+			}))},
+			want: want{
+				mg: virtualMachine(withExternalName("vm-1"), withParameters(v1alpha1.VirtualMachineParameters{
+					Name:     "api",
+					Metadata: map[string]string{"team": "api"},
+				}), withAtProvider(serverObservation), withConditions(xpv1.Available())),
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ConnectionDetails: serverConnectionDetails,
+					ResourceUpToDate:  false,
+					Diff:              `spec.forProvider.name: desired "api", observed "web"; spec.forProvider.metadata: desired map[team:api], observed map[team:web]`,
+				},
+			},
+		},
+		"RemovedMetadata": {
+			reason: "A VirtualMachine whose applied metadata key was removed from its parameters should need an update.",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(serverJSON))
+			},
+			args: args{mg: virtualMachine(withExternalName("vm-1"), withMetadataKeys("team"))},
+			want: want{
+				mg: virtualMachine(withExternalName("vm-1"), withAtProvider(serverObservation), withMetadataKeys("team"), withConditions(xpv1.Available())),
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ConnectionDetails: serverConnectionDetails,
+					ResourceUpToDate:  false,
+					Diff:              `spec.forProvider.metadata: desired map[], observed map[team:web]`,
+				},
+			},
+		},
+		"ImmutableDrift": {
+			reason: "A VirtualMachine whose immutable parameters differ from the server should report an error.",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(serverJSON))
+			},
+			args: args{mg: virtualMachine(withExternalName("vm-1"), withParameters(v1alpha1.VirtualMachineParameters{ImageRef: "image-2"}))},
+			want: want{
+				mg: virtualMachine(withExternalName("vm-1"), withParameters(v1alpha1.VirtualMachineParameters{ImageRef: "image-2"}),
+					withAtProvider(serverObservation), withConditions(xpv1.Available())),
+				err: errors.New(`cannot change fields in place: spec.forProvider.imageRef: desired "image-2", observed "image-1"`),
+			},
+		},
+		"ObserveOnlyImmutableDrift": {
+			reason: "Drift of an observe only VirtualMachine should not be reported as an error.",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(serverJSON))
+			},
+			args: args{mg: virtualMachine(withExternalName("vm-1"), withManagementPolicies(xpv1.ManagementActionObserve),
+				withParameters(v1alpha1.VirtualMachineParameters{ImageRef: "image-2"}))},
+			want: want{
+				mg: virtualMachine(withExternalName("vm-1"), withManagementPolicies(xpv1.ManagementActionObserve),
+					withParameters(v1alpha1.VirtualMachineParameters{ImageRef: "image-2"}),
+					withAtProvider(serverObservation), withConditions(xpv1.Available())),
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ConnectionDetails: serverConnectionDetails,
+					ResourceUpToDate:  true,
+					Diff:              `spec.forProvider.imageRef: desired "image-2", observed "image-1"`,
+				},
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			e := external{service: tc.fields.service}
-			got, err := e.Observe(tc.args.ctx, tc.args.mg)
+			e := external{service: newService(t, tc.handler), logger: logging.NewNopLogger()}
+			got, err := e.Observe(context.Background(), tc.args.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
 			}
 			if diff := cmp.Diff(tc.want.o, got); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
 			}
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want managed resource, +got managed resource:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// createParameters sets every field of VirtualMachineParameters, so that
+// TestCreate fails when a new field is not reflected in the golden request.
+// The user data references and template replace UserData, and are covered
+// by TestResolveContents and TestRenderUserData.
+var createParameters = v1alpha1.VirtualMachineParameters{
+	Name:             "web",
+	Description:      "web server",
+	ProjectId:        "project-1",
+	CellId:           "cell-1",
+	AccessIPV4:       "10.0.0.5",
+	AccessIPV6:       "2001:db8::5",
+	ImageRef:         "image-1",
+	FlavorRef:        "flavor-1",
+	AvailabilityZone: "az-1",
+	UserData:         "I2Nsb3VkLWNvbmZpZw==",
+	BlockDeviceMapping: []v1alpha1.BlockDeviceParameters{{
+		BootIndex:           1,
+		DeleteOnTermination: true,
+		DeviceName:          "vdb",
+		SourceType:          "volume",
+		DestinationType:     "volume",
+		VolumeSize:          100,
+		VolumeType:          "ssd",
+		UUID:                "vol-1",
+	}},
+	Metadata: map[string]string{"team": "web"},
+	Personality: []v1alpha1.PersonalityParameters{
+		{Path: "/etc/motd", Contents: "aGVsbG8="},
+		{Path: "/etc/token", ContentsSecretRef: &xpv1.SecretKeySelector{
+			SecretReference: xpv1.SecretReference{Name: "vm", Namespace: "default"},
+			Key:             "token",
+		}},
+	},
+	SecurityGroups: []v1alpha1.SecurityGroupParameters{{Name: "default"}},
+	PowerState:     v1alpha1.PowerStateStopped,
+}
+
+var createExempt = []string{
+	"VirtualMachineParameters.UserDataSecretRef",
+	"VirtualMachineParameters.UserDataConfigMapRef",
+	"VirtualMachineParameters.UserDataTemplate",
+	// Volume references are resolved into UUID before the VirtualMachine is
+	// created.
+	"VirtualMachineParameters.BlockDeviceMapping.VolumeRef",
+	"VirtualMachineParameters.BlockDeviceMapping.VolumeSelector",
+}
+
+// unset returns the paths of the fields of v that have their zero value. A
+// field of the elements of a slice is only unset if no element sets it.
+func unset(v reflect.Value, path string) []string {
+	switch v.Kind() { //nolint:exhaustive // Only containers need to be walked.
+	case reflect.Struct:
+		var out []string
+		for i := range v.NumField() {
+			out = append(out, unset(v.Field(i), path+"."+v.Type().Field(i).Name)...)
+		}
+		return out
+	case reflect.Slice:
+		if v.Len() == 0 {
+			return []string{path}
+		}
+		out := unset(v.Index(0), path)
+		for i := 1; i < v.Len(); i++ {
+			u := unset(v.Index(i), path)
+			out = slices.DeleteFunc(out, func(p string) bool { return !slices.Contains(u, p) })
+		}
+		return out
+	case reflect.Pointer:
+		if v.IsNil() {
+			return []string{path}
+		}
+		return unset(v.Elem(), path)
+	}
+	if v.IsZero() {
+		return []string{path}
+	}
+	return nil
+}
+
+func TestCreate(t *testing.T) {
+	u := slices.DeleteFunc(unset(reflect.ValueOf(createParameters), "VirtualMachineParameters"), func(p string) bool {
+		return slices.Contains(createExempt, p)
+	})
+	if len(u) > 0 {
+		t.Fatalf("createParameters must set every field, but does not set %v", u)
+	}
+
+	var body []byte
+	svc := newService(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		_, _ = w.Write([]byte(`{"server": {"id": "vm-1", "adminPass": "secret"}}`))
+	})
+	kube := &test.MockClient{MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+		obj.(*corev1.Secret).Data = map[string][]byte{"token": []byte("s3cr3t")}
+		return nil
+	}}
+	e := external{kube: kube, service: svc, logger: logging.NewNopLogger()}
+	cr := virtualMachine(withParameters(createParameters))
+
+	got, err := e.Create(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("e.Create(...): %v", err)
+	}
+	want := managed.ExternalCreation{ConnectionDetails: managed.ConnectionDetails{"adminPassword": []byte("secret")}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("e.Create(...): -want, +got:\n%s", diff)
+	}
+	if n := meta.GetExternalName(cr); n != "vm-1" {
+		t.Errorf("e.Create(...): want external name %q, got %q", "vm-1", n)
+	}
+	if a := cr.GetAnnotations(); a[userDataHashAnnotationKey] == "" || a[personalityHashAnnotationKey] == "" {
+		t.Errorf("e.Create(...): want the hashes of the contents in annotations, got %v", a)
+	}
+
+	var req bytes.Buffer
+	if err := json.Indent(&req, body, "", "  "); err != nil {
+		t.Fatalf("e.Create(...): invalid request %s: %v", body, err)
+	}
+	req.WriteString("\n")
+	golden := filepath.Join("testdata", "create.golden.json")
+	if *update {
+		if err := os.WriteFile(golden, req.Bytes(), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wantReq, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("cannot read %s, run go test with -update to create it: %v", golden, err)
+	}
+	if diff := cmp.Diff(string(wantReq), req.String()); diff != "" {
+		t.Errorf("e.Create(...): -want request, +got request:\n%s", diff)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	type args struct {
+		mg              resource.Managed
+		diff            drift.Diff
+		removedMetadata []string
+		powerAction     string
+	}
+
+	type want struct {
+		requests []string
+		err      error
+	}
+
+	cases := map[string]struct {
+		reason string
+		status int
+		args   args
+		want   want
+	}{
+		"NoDrift": {
+			reason: "No requests should be sent if nothing has drifted.",
+			args:   args{mg: virtualMachine(withExternalName("vm-1"))},
+		},
+		"NameAndDescription": {
+			reason: "A new name and description should be set with a single PUT.",
+			args: args{
+				mg: virtualMachine(withExternalName("vm-1"), withParameters(v1alpha1.VirtualMachineParameters{Name: "api", Description: "api server"})),
+				diff: drift.Diff{
+					{Path: "name", Desired: "api", Observed: "web", Mode: drift.Updatable},
+					{Path: "description", Desired: "api server", Observed: "imported", Mode: drift.Updatable},
+				},
+			},
+			want: want{requests: []string{`PUT /v3/servers/vm-1 {"server":{"name":"api","description":"api server"}}`}},
+		},
+		"Metadata": {
+			reason: "New metadata should be merged into the server metadata.",
+			args: args{
+				mg:   virtualMachine(withExternalName("vm-1"), withParameters(v1alpha1.VirtualMachineParameters{Metadata: map[string]string{"team": "api"}})),
+				diff: drift.Diff{{Path: "metadata", Mode: drift.Updatable}},
+			},
+			want: want{requests: []string{`POST /v3/servers/vm-1/metadata {"metadata":{"team":"api"}}`}},
+		},
+		"RemovedMetadata": {
+			reason: "Metadata keys removed from the parameters should be deleted from the server metadata.",
+			args: args{
+				mg:              virtualMachine(withExternalName("vm-1"), withParameters(v1alpha1.VirtualMachineParameters{Metadata: map[string]string{"team": "web"}})),
+				diff:            drift.Diff{{Path: "metadata", Mode: drift.Updatable}},
+				removedMetadata: []string{"owner"},
+			},
+			want: want{requests: []string{
+				`POST /v3/servers/vm-1/metadata {"metadata":{"team":"web"}}`,
+				`DELETE /v3/servers/vm-1/metadata/owner `,
+			}},
+		},
+		"Power": {
+			reason: "A power action should be requested to reach the desired power state.",
+			args:   args{mg: virtualMachine(withExternalName("vm-1")), powerAction: ucansdk.ActionStop},
+			want:   want{requests: []string{`POST /v3/servers/vm-1/action {"os-stop":null}`}},
+		},
+		"PowerTransitioning": {
+			reason: "A server that is already changing its power state should not be an error.",
+			status: http.StatusConflict,
+			args:   args{mg: virtualMachine(withExternalName("vm-1")), powerAction: ucansdk.ActionStop},
+			want:   want{requests: []string{`POST /v3/servers/vm-1/action {"os-stop":null}`}},
+		},
+		"UpdateError": {
+			reason: "Errors updating the server should be returned.",
+			status: http.StatusConflict,
+			args: args{
+				mg:   virtualMachine(withExternalName("vm-1"), withParameters(v1alpha1.VirtualMachineParameters{Name: "api"})),
+				diff: drift.Diff{{Path: "name", Desired: "api", Observed: "web", Mode: drift.Updatable}},
+			},
+			want: want{
+				requests: []string{`PUT /v3/servers/vm-1 {"server":{"name":"api"}}`},
+				err:      errors.Wrap(&ucansdk.APIError{StatusCode: http.StatusConflict}, "cannot update virtual machine"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var requests []string
+			svc := newService(t, func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+				if tc.status != 0 {
+					w.WriteHeader(tc.status)
+				}
+			})
+			e := external{service: svc, logger: logging.NewNopLogger(), diff: tc.args.diff, removedMetadata: tc.args.removedMetadata, powerAction: tc.args.powerAction}
+			_, err := e.Update(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.requests, requests); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want requests, +got requests:\n%s\n", tc.reason, diff)
+			}
 		})
 	}
 }
@@ -19,26 +19,28 @@ package volume
 import (
 	"context"
 	"encoding/json"
-	"fmt"
-	"net/http"
 
 	"github.com/pkg/errors"
-	"k8s.io/apimachinery/pkg/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/connection"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	"github.com/crossplane/provider-ucan/apis/osgalaxy/v1alpha1"
 	apisv1alpha1 "github.com/crossplane/provider-ucan/apis/v1alpha1"
+	"github.com/crossplane/provider-ucan/internal/clients"
+	"github.com/crossplane/provider-ucan/internal/drift"
+	"github.com/crossplane/provider-ucan/internal/externalname"
 	"github.com/crossplane/provider-ucan/internal/features"
 	"github.com/crossplane/provider-ucan/pkg/httpclient"
 	"github.com/crossplane/provider-ucan/pkg/ucansdk"
@@ -47,30 +49,25 @@ import (
 const (
 	errNotVolume    = "managed resource is not a Volume custom resource"
 	errTrackPCUsage = "cannot track ProviderConfig usage"
-	errGetPC        = "cannot get ProviderConfig"
-	errGetCreds     = "cannot get credentials"
 	errNewClient    = "cannot create new Service"
-
+	errUpdate       = "cannot update volume"
+	errExtend       = "cannot extend volume"
+	errShrink       = "volume size cannot be decreased from %d to %d"
+	errExtending    = "volume is being extended"
+	errExtendFailed = "volume failed to extend; reset its status in UCAN to retry"
+	errRetype       = "cannot retype volume"
+	errRetyping     = "volume is being retyped"
+	errRetypeFailed = "volume failed to retype to %q; change spec.forProvider.volumeType to retry"
+
+	// volumeUUIDAnnotationKey held the UCAN ID before the external-name annotation
+	// was adopted. It is migrated by the LegacyAnnotationMigrator.
 	volumeUUIDAnnotationKey = "ucan.io/volume-uuid"
 )
 
 type UcanClient struct {
-	HttpClient *httpclient.HttpClient
+	*ucansdk.Client
 }
 
-var (
-	newUcanClient = func(credentials []byte) (*UcanClient, error) {
-		var signCertificate httpclient.SignCertificate
-		if err := json.Unmarshal(credentials, &signCertificate); err != nil {
-			fmt.Println("*************cannot get credentials*************")
-			return nil, err
-		}
-		cli := httpclient.NewHttpClient(signCertificate)
-		cli.SetHeader("Content-Type", "application/json")
-		return &UcanClient{HttpClient: cli}, nil
-	}
-)
-
 // Setup adds a controller that reconciles Volume managed resources.
 func Setup(mgr ctrl.Manager, o controller.Options) error {
 	name := managed.ControllerName(v1alpha1.VolumeGroupKind)
@@ -80,16 +77,24 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
 	}
 
-	r := managed.NewReconciler(mgr,
-		resource.ManagedKind(v1alpha1.VolumeGroupVersionKind),
+	log := o.Logger.WithValues("controller", name)
+	opts := []managed.ReconcilerOption{
 		managed.WithExternalConnecter(&connector{
-			kube:         mgr.GetClient(),
-			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
-			newServiceFn: newUcanClient}),
-		managed.WithLogger(o.Logger.WithValues("controller", name)),
+			kube:    mgr.GetClient(),
+			usage:   resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			clients: clients.DefaultCache(),
+			logger:  log}),
+		managed.WithInitializers(externalname.NewLegacyAnnotationMigrator(mgr.GetClient(), volumeUUIDAnnotationKey)),
+		managed.WithLogger(log),
 		managed.WithPollInterval(o.PollInterval),
 		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-		managed.WithConnectionPublishers(cps...))
+		managed.WithConnectionPublishers(cps...),
+	}
+	if o.Features.Enabled(features.EnableAlphaManagementPolicies) {
+		opts = append(opts, managed.WithManagementPolicies())
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1alpha1.VolumeGroupVersionKind), opts...)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
@@ -100,9 +105,10 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 }
 
 type connector struct {
-	kube         client.Client
-	usage        resource.Tracker
-	newServiceFn func(creds []byte) (*UcanClient, error)
+	kube    client.Client
+	usage   resource.Tracker
+	clients *clients.Cache
+	logger  logging.Logger
 }
 
 func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
@@ -115,30 +121,24 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errTrackPCUsage)
 	}
 
-	pc := &apisv1alpha1.ProviderConfig{}
-	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.GetProviderConfigReference().Name}, pc); err != nil {
-		return nil, errors.Wrap(err, errGetPC)
-	}
-
-	cd := pc.Spec.Credentials
-	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.kube, cd.CommonCredentialSelectors)
-	if err != nil {
-		return nil, errors.Wrap(err, errGetCreds)
-	}
-
-	svc, err := c.newServiceFn(data)
+	svc, err := c.clients.Get(ctx, c.kube, mg)
 	if err != nil {
 		return nil, errors.Wrap(err, errNewClient)
 	}
 
-	zl := zap.New(zap.UseDevMode(true))
-	log := logging.NewLogrLogger(zl.WithName("provider-volume"))
-	return &external{service: svc, logger: log}, nil
+	log := c.logger.WithValues("name", cr.GetName(), "project", cr.Spec.ForProvider.ProjectId, "externalID", meta.GetExternalName(cr))
+	return &external{service: &UcanClient{Client: svc.WithOptions(httpclient.WithLogger(log))}, logger: log}, nil
 }
 
 type external struct {
 	service *UcanClient
 	logger  logging.Logger
+
+	// diff is the drift found by the last call to Observe. status and
+	// migrationStatus are the observed statuses of the volume.
+	diff            drift.Diff
+	status          string
+	migrationStatus string
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -147,44 +147,85 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.New(errNotVolume)
 	}
 
-	uuid, ok := cr.GetAnnotations()[volumeUUIDAnnotationKey]
-	if !ok {
-		c.logger.Info("volume Resource Status", "msg", "uuid not found")
+	uuid := meta.GetExternalName(cr)
+	if uuid == "" {
+		c.logger.Debug("External resource has no ID")
 		return managed.ExternalObservation{
 			ResourceExists: false,
 		}, nil
 	}
 
-	volume, code, err := ucansdk.GetVolume(c.service.HttpClient, cr.Spec.ForProvider.ProjectId, uuid)
+	volume, err := ucansdk.GetVolume(ctx, c.service.Client, cr.Spec.ForProvider.ProjectId, uuid)
+	if ucansdk.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
 	if err != nil {
-		c.logger.Info("volume Resource err", "msg", err)
+		c.logger.Debug("Cannot get volume", "error", err)
 		return managed.ExternalObservation{}, errors.Wrap(err, "cannot get volume")
 	}
-	if code >= http.StatusBadRequest && code != http.StatusNotFound {
-		c.logger.Info("get Resource err", "code", code, "body", string(volume))
-		return managed.ExternalObservation{}, errors.New("cannot get volume")
-	}
-	resourceExists := code != http.StatusNotFound
 
 	var response ucansdk.VolumeResp
 	if err = json.Unmarshal(volume, &response); err != nil {
-		c.logger.Info("unmarshal err create Resource", "msg", err)
+		c.logger.Debug("Cannot unmarshal volume", "error", err)
 		return managed.ExternalObservation{}, errors.Wrap(err, "cannot unmarshal volume")
 	}
-	c.logger.Info("get Resource", "code", code, "name", response.Volume.Name, "status", response.Volume.Status, "uuid", response.Volume.ID)
-	cr.Status.AtProvider = v1alpha1.VolumeObservation{
-		Status: response.Volume.Status,
-	}
-	if resourceExists && response.Volume.Status == "available" {
+	c.logger.Debug("Observed volume", "status", response.Volume.Status)
+	// The progress of a retype is recorded by the provider, and cannot be
+	// observed.
+	prev := cr.Status.AtProvider
+	cr.Status.AtProvider = generateObservation(response)
+	cr.Status.AtProvider.Retype = prev.Retype
+	// An attached volume is as usable as a detached one, e.g. as the target
+	// of a reference.
+	if response.Volume.Status == ucansdk.VolumeStatusAvailable || response.Volume.Status == ucansdk.VolumeStatusInUse {
 		// 将状态置为可用
 		cr.SetConditions(xpv1.Available())
 	}
 
-	return managed.ExternalObservation{
-		ResourceExists:    resourceExists,
-		ResourceUpToDate:  true,
-		ConnectionDetails: managed.ConnectionDetails{},
-	}, nil
+	c.status = response.Volume.Status
+	c.migrationStatus = cr.Status.AtProvider.MigrationStatus
+	c.observeRetype(cr)
+	c.diff = generateDiff(cr, response)
+	if len(c.diff) > 0 {
+		c.logger.Debug("Observed drift", "diff", c.diff.String())
+	}
+
+	o, err := drift.Observation(cr, c.diff)
+	if err != nil && c.diff.Immutable().Has("size") {
+		// A volume can never shrink, so a smaller size gets its own error.
+		err = errors.Errorf(errShrink, response.Volume.Size, cr.Spec.ForProvider.Size)
+	}
+	// Report the volume as out of date until it has been extended or
+	// retyped, so that it is not Synced before the change is in effect.
+	o.ResourceUpToDate = o.ResourceUpToDate && !c.changing()
+	return o, err
+}
+
+// changing returns true while the volume is being extended or retyped.
+func (c *external) changing() bool {
+	switch {
+	case c.status == ucansdk.VolumeStatusExtending, c.status == ucansdk.VolumeStatusRetyping:
+		return true
+	case c.migrationStatus == ucansdk.MigrationStatusMigrating, c.migrationStatus == ucansdk.MigrationStatusCompleting:
+		return true
+	}
+	return false
+}
+
+// observeRetype records the outcome of the last volume type change in the
+// status of cr. UCAN marks a volume as retyping before it accepts a retype, so
+// a volume that settles on another type than the one requested failed to
+// retype, e.g. because UCAN rejected it or its migration failed.
+func (c *external) observeRetype(cr *v1alpha1.Volume) {
+	r := cr.Status.AtProvider.Retype
+	if r == nil || r.Phase != v1alpha1.RetypePhaseRetyping || c.changing() {
+		return
+	}
+	if cr.Status.AtProvider.VolumeType == r.ToVolumeType {
+		r.Phase = v1alpha1.RetypePhaseRetyped
+		return
+	}
+	r.Phase = v1alpha1.RetypePhaseFailed
 }
 
 func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
@@ -202,34 +243,28 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 			VolumeType:  &cr.Spec.ForProvider.VolumeType,
 			CellID:      cr.Spec.ForProvider.CellId,
 		},
-		OSSCHSchedulerHints: ucansdk.VolumeSchedulerHints{
-			SameHost: cr.Spec.ForProvider.SchedulerHints[0].SameHost,
-		},
+	}
+	if len(cr.Spec.ForProvider.SchedulerHints) > 0 {
+		req.OSSCHSchedulerHints.SameHost = cr.Spec.ForProvider.SchedulerHints[0].SameHost
 	}
 	reqData, err := json.Marshal(req)
 	if err != nil {
-		c.logger.Info("Marshal err create Resource", "msg", err)
+		c.logger.Debug("Cannot marshal volume request", "error", err)
 		return managed.ExternalCreation{}, errors.Wrap(err, "cannot create volume")
 	}
-	volume, code, err := ucansdk.CreateVolume(c.service.HttpClient, reqData, cr.Spec.ForProvider.ProjectId)
+	volume, err := ucansdk.CreateVolume(ctx, c.service.Client, reqData, cr.Spec.ForProvider.ProjectId)
 	if err != nil {
-		c.logger.Info("create Resource err", "msg", err)
+		c.logger.Debug("Cannot create volume", "error", err)
 		return managed.ExternalCreation{}, errors.Wrap(err, "cannot create volume")
 	}
-	if code >= http.StatusBadRequest {
-		c.logger.Info("create Resource err", "code", code, "body", string(volume))
-		return managed.ExternalCreation{}, errors.New("cannot create volume")
-	}
 	var response ucansdk.VolumeResp
 	if err = json.Unmarshal(volume, &response); err != nil {
-		c.logger.Info("unmarshal err create Resource", "msg", err)
+		c.logger.Debug("Cannot unmarshal volume", "error", err)
 		return managed.ExternalCreation{}, errors.Wrap(err, "cannot unmarshal volume")
 	}
-	c.logger.Info("unmarshal Resource", "id", response.Volume.ID, "name", response.Volume.Name)
+	c.logger.Debug("Created volume", "externalID", response.Volume.ID)
 
-	mg.SetAnnotations(map[string]string{
-		volumeUUIDAnnotationKey: response.Volume.ID,
-	})
+	meta.SetExternalName(cr, response.Volume.ID)
 	return managed.ExternalCreation{
 		ConnectionDetails: managed.ConnectionDetails{},
 	}, nil
@@ -241,13 +276,76 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, errors.New(errNotVolume)
 	}
 
-	fmt.Printf("Updating: %+v\n", cr.Spec.ForProvider)
+	// The volume is not Synced until the change is in effect. The new size
+	// or type is observed once the extend or retype has completed.
+	if c.status == ucansdk.VolumeStatusExtending {
+		return managed.ExternalUpdate{}, errors.New(errExtending)
+	}
+	if c.changing() {
+		return managed.ExternalUpdate{}, errors.New(errRetyping)
+	}
 
-	return managed.ExternalUpdate{
-		// Optionally return any details that may be required to connect to the
-		// external resource. These will be stored as the connection secret.
-		ConnectionDetails: managed.ConnectionDetails{},
-	}, nil
+	diff := c.diff.Updatable()
+	c.logger.Debug("Updating volume", "diff", diff.String())
+
+	if diff.Has("name") || diff.Has("description") {
+		reqData, err := json.Marshal(ucansdk.UpdateVolumeReq{Volume: ucansdk.UpdateVolumeParams{
+			Name:        cr.Spec.ForProvider.Name,
+			Description: cr.Spec.ForProvider.Description,
+		}})
+		if err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errUpdate)
+		}
+		if _, err := ucansdk.UpdateVolume(ctx, c.service.Client, cr.Spec.ForProvider.ProjectId, meta.GetExternalName(cr), reqData); err != nil {
+			c.logger.Debug("Cannot update volume", "error", err)
+			return managed.ExternalUpdate{}, errors.Wrap(err, errUpdate)
+		}
+	}
+
+	if diff.Has("size") {
+		if c.status == ucansdk.VolumeStatusErrorExtending {
+			return managed.ExternalUpdate{}, errors.New(errExtendFailed)
+		}
+		reqData, err := json.Marshal(ucansdk.ExtendVolumeReq{Extend: ucansdk.ExtendVolumeParams{NewSize: int(cr.Spec.ForProvider.Size)}})
+		if err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errExtend)
+		}
+		if _, err := ucansdk.VolumeAction(ctx, c.service.Client, cr.Spec.ForProvider.ProjectId, meta.GetExternalName(cr), reqData); err != nil {
+			c.logger.Debug("Cannot extend volume", "error", err)
+			return managed.ExternalUpdate{}, errors.Wrap(err, errExtend)
+		}
+		c.logger.Debug("Requested extend of volume", "size", cr.Spec.ForProvider.Size)
+
+		// The volume cannot be retyped while it is extending. Any retype
+		// is requested once the extend has completed.
+		return managed.ExternalUpdate{}, nil
+	}
+
+	if diff.Has("volumeType") {
+		// A retype that failed is not retried until the desired type changes.
+		if r := cr.Status.AtProvider.Retype; r != nil && r.Phase == v1alpha1.RetypePhaseFailed && r.ToVolumeType == cr.Spec.ForProvider.VolumeType {
+			return managed.ExternalUpdate{}, errors.Errorf(errRetypeFailed, r.ToVolumeType)
+		}
+		reqData, err := json.Marshal(ucansdk.RetypeVolumeReq{Retype: ucansdk.RetypeVolumeParams{
+			NewType:         cr.Spec.ForProvider.VolumeType,
+			MigrationPolicy: cr.Spec.ForProvider.MigrationPolicy,
+		}})
+		if err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errRetype)
+		}
+		if _, err := ucansdk.VolumeAction(ctx, c.service.Client, cr.Spec.ForProvider.ProjectId, meta.GetExternalName(cr), reqData); err != nil {
+			c.logger.Debug("Cannot retype volume", "error", err)
+			return managed.ExternalUpdate{}, errors.Wrap(err, errRetype)
+		}
+		c.logger.Debug("Requested retype of volume", "volumeType", cr.Spec.ForProvider.VolumeType, "migrationPolicy", cr.Spec.ForProvider.MigrationPolicy)
+		cr.Status.AtProvider.Retype = &v1alpha1.RetypeObservation{
+			FromVolumeType: cr.Status.AtProvider.VolumeType,
+			ToVolumeType:   cr.Spec.ForProvider.VolumeType,
+			Phase:          v1alpha1.RetypePhaseRetyping,
+		}
+	}
+
+	return managed.ExternalUpdate{}, nil
 }
 
 func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
@@ -256,21 +354,17 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalDelete{}, errors.New(errNotVolume)
 	}
 
-	uuid, ok := cr.GetAnnotations()[volumeUUIDAnnotationKey]
-	if !ok {
-		c.logger.Info("volume Resource Status", "name", cr.Name, "msg", "uuid not found")
+	uuid := meta.GetExternalName(cr)
+	if uuid == "" {
+		c.logger.Debug("External resource has no ID")
 		return managed.ExternalDelete{}, nil
 	}
-	body, code, err := ucansdk.DelVolume(c.service.HttpClient, cr.Spec.ForProvider.ProjectId, uuid)
-	if err != nil {
-		c.logger.Info("delete Resource err", "name", cr.Name, "msg", err)
+	_, err := ucansdk.DelVolume(ctx, c.service.Client, cr.Spec.ForProvider.ProjectId, uuid)
+	if err != nil && !ucansdk.IsNotFound(err) {
+		c.logger.Debug("Cannot delete volume", "error", err)
 		return managed.ExternalDelete{}, errors.Wrap(err, "cannot delete volume")
 	}
-	if code != http.StatusNoContent {
-		c.logger.Info("delete Resource err", "name", cr.Name, "code", code, "body", string(body))
-		return managed.ExternalDelete{}, errors.New("cannot delete volume")
-	}
-	c.logger.Info("delete Resource", "name", cr.Name, "code", code)
+	c.logger.Debug("Deleted volume")
 
 	return managed.ExternalDelete{}, nil
 }
@@ -278,3 +372,69 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.Ext
 func (c *external) Disconnect(ctx context.Context) error {
 	return nil
 }
+
+// generateObservation projects the UCAN volume onto the observable fields of a
+// Volume.
+func generateObservation(resp ucansdk.VolumeResp) v1alpha1.VolumeObservation {
+	v := resp.Volume
+	o := v1alpha1.VolumeObservation{
+		ID:                v.ID,
+		Name:              ptr.Deref(v.Name, ""),
+		Description:       ptr.Deref(v.Description, ""),
+		ProjectId:         v.ProjectID,
+		UserId:            v.UserID,
+		VolumeType:        v.VolumeType,
+		Size:              int64(v.Size),
+		Multiattach:       v.Multiattach,
+		AvailabilityZone:  v.AvailabilityZone,
+		Status:            v.Status,
+		MigrationStatus:   ptr.Deref(v.MigrationStatus, ""),
+		Bootable:          v.Bootable,
+		Encrypted:         v.Encrypted,
+		Host:              ptr.Deref(v.Host, ""),
+		ReplicationStatus: ptr.Deref(v.ReplicationStatus, ""),
+		SourceVolumeID:    ptr.Deref(v.SourceVolid, ""),
+		SnapshotID:        ptr.Deref(v.SnapshotID, ""),
+	}
+	if !v.CreatedAt.IsZero() {
+		o.Created = &metav1.Time{Time: v.CreatedAt}
+	}
+	if v.UpdatedAt != nil {
+		o.Updated = &metav1.Time{Time: *v.UpdatedAt}
+	}
+	for _, a := range v.Attachments {
+		at := v1alpha1.AttachmentObservation{
+			ID:           a.ID,
+			ServerID:     a.ServerID,
+			Device:       a.Device,
+			HostName:     ptr.Deref(a.HostName, ""),
+			AttachmentID: a.AttachmentID,
+		}
+		if a.AttachedAt != nil {
+			at.AttachedAt = &metav1.Time{Time: *a.AttachedAt}
+		}
+		o.Attachments = append(o.Attachments, at)
+	}
+	return o
+}
+
+// generateDiff compares the parameters of a Volume with the UCAN volume.
+func generateDiff(cr *v1alpha1.Volume, resp ucansdk.VolumeResp) drift.Diff {
+	p := cr.Spec.ForProvider
+	v := resp.Volume
+
+	var d drift.Diff
+	drift.Compare(&d, "name", p.Name, ptr.Deref(v.Name, ""), drift.Updatable)
+	drift.Compare(&d, "description", p.Description, ptr.Deref(v.Description, ""), drift.Updatable)
+	// Volumes can grow, but never shrink.
+	sizeMode := drift.Updatable
+	if p.Size < int64(v.Size) {
+		sizeMode = drift.Immutable
+	}
+	drift.Compare(&d, "size", p.Size, int64(v.Size), sizeMode)
+	drift.Compare(&d, "volumeType", p.VolumeType, v.VolumeType, drift.Updatable)
+	drift.Compare(&d, "projectId", p.ProjectId, v.ProjectID, drift.Immutable)
+	drift.Compare(&d, "multiattach", p.Multiattach, v.Multiattach, drift.Immutable)
+	drift.Compare(&d, "availabilityZone", p.AvailabilityZone, v.AvailabilityZone, drift.Immutable)
+	return d
+}
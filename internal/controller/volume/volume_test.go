@@ -18,13 +18,29 @@ package volume
 
 import (
 	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-ucan/apis/osgalaxy/v1alpha1"
+	"github.com/crossplane/provider-ucan/internal/drift"
+	"github.com/crossplane/provider-ucan/pkg/httpclient"
+	"github.com/crossplane/provider-ucan/pkg/ucansdk"
 )
 
 // Unlike many Kubernetes projects Crossplane does not use third party testing
@@ -35,40 +51,503 @@ import (
 // https://github.com/golang/go/wiki/TestComments
 // https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
 
-func TestObserve(t *testing.T) {
-	type fields struct {
-		service *UcanClient
+const volJSON = `{"volume": {
+	"id": "vol-1",
+	"name": "data",
+	"description": "imported",
+	"os-vol-tenant-attr:tenant_id": "project-1",
+	"user_id": "user-1",
+	"volume_type": "ssd",
+	"size": 100,
+	"multiattach": true,
+	"availability_zone": "az-1",
+	"status": "available",
+	"bootable": true,
+	"created_at": "2025-01-02T03:04:05Z",
+	"os-vol-host-attr:host": "host-1@ssd#pool",
+	"attachments": [{
+		"id": "vol-1",
+		"attachment_id": "att-1",
+		"volume_id": "vol-1",
+		"server_id": "vm-1",
+		"host_name": "host-1",
+		"device": "/dev/vdb",
+		"attached_at": "2025-01-02T03:05:00Z"
+	}]
+}}`
+
+var volumeObservation = v1alpha1.VolumeObservation{
+	ID:               "vol-1",
+	Name:             "data",
+	Description:      "imported",
+	ProjectId:        "project-1",
+	UserId:           "user-1",
+	VolumeType:       "ssd",
+	Size:             100,
+	Multiattach:      true,
+	AvailabilityZone: "az-1",
+	Status:           "available",
+	Bootable:         true,
+	Host:             "host-1@ssd#pool",
+	Created:          &metav1.Time{Time: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)},
+	Attachments: []v1alpha1.AttachmentObservation{{
+		ID:           "vol-1",
+		AttachmentID: "att-1",
+		ServerID:     "vm-1",
+		HostName:     "host-1",
+		Device:       "/dev/vdb",
+		AttachedAt:   &metav1.Time{Time: time.Date(2025, 1, 2, 3, 5, 0, 0, time.UTC)},
+	}},
+}
+
+type volModifier func(*v1alpha1.Volume)
+
+func withExternalName(n string) volModifier {
+	return func(cr *v1alpha1.Volume) { meta.SetExternalName(cr, n) }
+}
+
+func withProjectId(p string) volModifier {
+	return func(cr *v1alpha1.Volume) { cr.Spec.ForProvider.ProjectId = p }
+}
+
+func withSize(v int64) volModifier {
+	return func(cr *v1alpha1.Volume) { cr.Spec.ForProvider.Size = v }
+}
+
+func withVolumeType(t string) volModifier {
+	return func(cr *v1alpha1.Volume) { cr.Spec.ForProvider.VolumeType = t }
+}
+
+func withRetype(r *v1alpha1.RetypeObservation) volModifier {
+	return func(cr *v1alpha1.Volume) { cr.Status.AtProvider.Retype = r }
+}
+
+func withManagementPolicies(p ...xpv1.ManagementAction) volModifier {
+	return func(cr *v1alpha1.Volume) { cr.SetManagementPolicies(p) }
+}
+
+func withAtProvider(o v1alpha1.VolumeObservation) volModifier {
+	return func(cr *v1alpha1.Volume) { cr.Status.AtProvider = o }
+}
+
+func withConditions(c ...xpv1.Condition) volModifier {
+	return func(cr *v1alpha1.Volume) { cr.SetConditions(c...) }
+}
+
+func volume(m ...volModifier) *v1alpha1.Volume {
+	cr := &v1alpha1.Volume{}
+	for _, f := range m {
+		f(cr)
 	}
+	return cr
+}
 
+// newService returns a UcanClient whose endpoints are served by h.
+func newService(t *testing.T, h http.HandlerFunc) *UcanClient {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	ep := ucansdk.Endpoint{BaseURL: srv.URL}
+	cli := httpclient.NewHttpClient(httpclient.SignCertificate{AccessKeyID: "ak", SecretAccessKey: "sk"}, httpclient.WithRetryPolicy(httpclient.RetryPolicy{MaxAttempts: 1}))
+	return &UcanClient{Client: ucansdk.NewClient(cli, ucansdk.Endpoints{VirtualMachine: ep, Volume: ep, Network: ep})}
+}
+
+func TestObserve(t *testing.T) {
 	type args struct {
-		ctx context.Context
-		mg  resource.Managed
+		mg resource.Managed
 	}
 
 	type want struct {
+		mg  resource.Managed
 		o   managed.ExternalObservation
 		err error
 	}
 
 	cases := map[string]struct {
-		reason string
-		fields fields
-		args   args
-		want   want
+		reason  string
+		handler http.HandlerFunc
+		args    args
+		want    want
 	}{
-		// TODO: Add test cases.
+		"NoExternalName": {
+			reason: "A Volume without an external name should not exist.",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			args: args{mg: volume(withProjectId("project-1"))},
+			want: want{
+				mg: volume(withProjectId("project-1")),
+				o:  managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"NotFound": {
+			reason: "A Volume that UCAN cannot find should not exist.",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			args: args{mg: volume(withProjectId("project-1"), withExternalName("vol-1"))},
+			want: want{
+				mg: volume(withProjectId("project-1"), withExternalName("vol-1")),
+				o:  managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"GetError": {
+			reason: "Errors getting the Volume should be returned.",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusForbidden)
+			},
+			args: args{mg: volume(withProjectId("project-1"), withExternalName("vol-1"))},
+			want: want{
+				mg:  volume(withProjectId("project-1"), withExternalName("vol-1")),
+				err: errors.Wrap(&ucansdk.APIError{StatusCode: http.StatusForbidden}, "cannot get volume"),
+			},
+		},
+		"ObserveOnlyImport": {
+			reason: "An imported Volume should have its remote state reported in status.atProvider.",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet || r.URL.Path != "/v3/project-1/volumes/vol-1" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				_, _ = w.Write([]byte(volJSON))
+			},
+			args: args{mg: volume(withProjectId("project-1"), withExternalName("vol-1"), withManagementPolicies(xpv1.ManagementActionObserve))},
+			want: want{
+				mg: volume(
+					withProjectId("project-1"),
+					withExternalName("vol-1"),
+					withManagementPolicies(xpv1.ManagementActionObserve),
+					withAtProvider(volumeObservation),
+					withConditions(xpv1.Available()),
+				),
+				o: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"Shrink": {
+			reason: "A Volume should never shrink.",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(volJSON))
+			},
+			args: args{mg: volume(withProjectId("project-1"), withExternalName("vol-1"), withSize(50))},
+			want: want{
+				mg: volume(withProjectId("project-1"), withExternalName("vol-1"), withSize(50),
+					withAtProvider(volumeObservation), withConditions(xpv1.Available())),
+				err: errors.Errorf(errShrink, 100, 50),
+			},
+		},
+		"InUse": {
+			reason: "An attached Volume should be available.",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(strings.Replace(volJSON, `"available"`, `"in-use"`, 1)))
+			},
+			args: args{mg: volume(withProjectId("project-1"), withExternalName("vol-1"))},
+			want: want{
+				mg: volume(withProjectId("project-1"), withExternalName("vol-1"),
+					withAtProvider(func() v1alpha1.VolumeObservation {
+						o := volumeObservation
+						o.Status = "in-use"
+						return o
+					}()), withConditions(xpv1.Available())),
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"Extending": {
+			reason: "A Volume should not be up to date while it is being extended.",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(strings.Replace(volJSON, `"available"`, `"extending"`, 1)))
+			},
+			args: args{mg: volume(withProjectId("project-1"), withExternalName("vol-1"), withSize(100))},
+			want: want{
+				mg: volume(withProjectId("project-1"), withExternalName("vol-1"), withSize(100),
+					withAtProvider(func() v1alpha1.VolumeObservation {
+						o := volumeObservation
+						o.Status = "extending"
+						return o
+					}())),
+				o: managed.ExternalObservation{ResourceExists: true},
+			},
+		},
+		"UpdatableDrift": {
+			reason: "A Volume whose updatable parameters differ from UCAN should need an update.",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(volJSON))
+			},
+			args: args{mg: volume(withProjectId("project-1"), withExternalName("vol-1"), withSize(200))},
+			want: want{
+				mg: volume(withProjectId("project-1"), withExternalName("vol-1"), withSize(200),
+					withAtProvider(volumeObservation), withConditions(xpv1.Available())),
+				o: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: false,
+					Diff:             "spec.forProvider.size: desired 200, observed 100",
+				},
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			e := external{service: tc.fields.service}
-			got, err := e.Observe(tc.args.ctx, tc.args.mg)
+			e := external{service: newService(t, tc.handler), logger: logging.NewNopLogger()}
+			got, err := e.Observe(context.Background(), tc.args.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
 			}
 			if diff := cmp.Diff(tc.want.o, got); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
 			}
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want managed resource, +got managed resource:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		params v1alpha1.VolumeParameters
+		want   []string
+	}{
+		"SchedulerHints": {
+			reason: "The first scheduler hints should be sent with the volume.",
+			params: v1alpha1.VolumeParameters{
+				ProjectId:      "project-1",
+				Size:           100,
+				SchedulerHints: []v1alpha1.SchedulerHintsParameters{{SameHost: []string{"vol-2"}}},
+			},
+			want: []string{"vol-2"},
+		},
+		"NoSchedulerHints": {
+			reason: "A volume without scheduler hints should be created without them.",
+			params: v1alpha1.VolumeParameters{ProjectId: "project-1", Size: 100},
+			want:   nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var req ucansdk.CreateVolumeReq
+			svc := newService(t, func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&req)
+				_, _ = w.Write([]byte(volJSON))
+			})
+			e := external{service: svc, logger: logging.NewNopLogger()}
+			cr := volume(func(cr *v1alpha1.Volume) { cr.Spec.ForProvider = tc.params })
+			if _, err := e.Create(context.Background(), cr); err != nil {
+				t.Fatalf("\n%s\ne.Create(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, req.OSSCHSchedulerHints.SameHost); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want same host hint, +got same host hint:\n%s\n", tc.reason, diff)
+			}
+			if n := meta.GetExternalName(cr); n != "vol-1" {
+				t.Errorf("\n%s\ne.Create(...): want external name %q, got %q", tc.reason, "vol-1", n)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	type args struct {
+		mg              resource.Managed
+		diff            drift.Diff
+		status          string
+		migrationStatus string
+	}
+
+	type want struct {
+		requests []string
+		retype   *v1alpha1.RetypeObservation
+		err      error
+	}
+
+	failed := &v1alpha1.RetypeObservation{FromVolumeType: "ssd", ToVolumeType: "hdd", Phase: v1alpha1.RetypePhaseFailed}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Rename": {
+			reason: "A new name or description should update the volume.",
+			args: args{
+				mg: volume(withProjectId("project-1"), withExternalName("vol-1"), func(cr *v1alpha1.Volume) {
+					cr.Spec.ForProvider.Name = "data"
+					cr.Spec.ForProvider.Description = "database"
+				}),
+				diff: drift.Diff{
+					{Path: "name", Desired: "data", Observed: "vol", Mode: drift.Updatable},
+					{Path: "description", Desired: "database", Observed: "", Mode: drift.Updatable},
+				},
+				status: ucansdk.VolumeStatusAvailable,
+			},
+			want: want{requests: []string{`PUT /v3/project-1/volumes/vol-1  {"volume":{"name":"data","description":"database"}}`}},
+		},
+		"RenameAndExtend": {
+			reason: "A volume should be renamed and extended in the same update.",
+			args: args{
+				mg: volume(withProjectId("project-1"), withExternalName("vol-1"), withSize(200), func(cr *v1alpha1.Volume) {
+					cr.Spec.ForProvider.Name = "data"
+				}),
+				diff: drift.Diff{
+					{Path: "name", Desired: "data", Observed: "vol", Mode: drift.Updatable},
+					{Path: "size", Desired: int64(200), Observed: int64(100), Mode: drift.Updatable},
+				},
+				status: ucansdk.VolumeStatusAvailable,
+			},
+			want: want{requests: []string{
+				`PUT /v3/project-1/volumes/vol-1  {"volume":{"name":"data"}}`,
+				`POST /v3/project-1/volumes/vol-1/action volume 3.42 {"os-extend":{"new_size":200}}`,
+			}},
+		},
+		"Extend": {
+			reason: "A larger size should extend the volume.",
+			args: args{
+				mg:     volume(withProjectId("project-1"), withExternalName("vol-1"), withSize(200)),
+				diff:   drift.Diff{{Path: "size", Desired: int64(200), Observed: int64(100), Mode: drift.Updatable}},
+				status: ucansdk.VolumeStatusInUse,
+			},
+			want: want{requests: []string{`POST /v3/project-1/volumes/vol-1/action volume 3.42 {"os-extend":{"new_size":200}}`}},
+		},
+		"Extending": {
+			reason: "No request should be sent, and the volume should not be Synced, while it is being extended.",
+			args: args{
+				mg:     volume(withProjectId("project-1"), withExternalName("vol-1"), withSize(200)),
+				status: ucansdk.VolumeStatusExtending,
+			},
+			want: want{err: errors.New(errExtending)},
+		},
+		"Retype": {
+			reason: "A new volume type should retype the volume with the migration policy.",
+			args: args{
+				mg: volume(withProjectId("project-1"), withExternalName("vol-1"), func(cr *v1alpha1.Volume) {
+					cr.Spec.ForProvider.VolumeType = "hdd"
+					cr.Spec.ForProvider.MigrationPolicy = v1alpha1.MigrationPolicyOnDemand
+				}),
+				diff:   drift.Diff{{Path: "volumeType", Desired: "hdd", Observed: "ssd", Mode: drift.Updatable}},
+				status: ucansdk.VolumeStatusAvailable,
+			},
+			want: want{
+				requests: []string{`POST /v3/project-1/volumes/vol-1/action volume 3.42 {"os-retype":{"new_type":"hdd","migration_policy":"on-demand"}}`},
+				retype:   &v1alpha1.RetypeObservation{ToVolumeType: "hdd", Phase: v1alpha1.RetypePhaseRetyping},
+			},
+		},
+		"RetypeFailed": {
+			reason: "A retype that failed should not be requested again.",
+			args: args{
+				mg:     volume(withProjectId("project-1"), withExternalName("vol-1"), withVolumeType("hdd"), withRetype(failed)),
+				diff:   drift.Diff{{Path: "volumeType", Desired: "hdd", Observed: "ssd", Mode: drift.Updatable}},
+				status: ucansdk.VolumeStatusAvailable,
+			},
+			want: want{retype: failed, err: errors.Errorf(errRetypeFailed, "hdd")},
+		},
+		"RetypeToAnotherType": {
+			reason: "A retype that failed should not stop a retype to another type.",
+			args: args{
+				mg:     volume(withProjectId("project-1"), withExternalName("vol-1"), withVolumeType("nvme"), withRetype(failed)),
+				diff:   drift.Diff{{Path: "volumeType", Desired: "nvme", Observed: "ssd", Mode: drift.Updatable}},
+				status: ucansdk.VolumeStatusAvailable,
+			},
+			want: want{
+				requests: []string{`POST /v3/project-1/volumes/vol-1/action volume 3.42 {"os-retype":{"new_type":"nvme"}}`},
+				retype:   &v1alpha1.RetypeObservation{ToVolumeType: "nvme", Phase: v1alpha1.RetypePhaseRetyping},
+			},
+		},
+		"ExtendBeforeRetype": {
+			reason: "A volume should be extended before it is retyped.",
+			args: args{
+				mg: volume(withProjectId("project-1"), withExternalName("vol-1"), withSize(200)),
+				diff: drift.Diff{
+					{Path: "size", Desired: int64(200), Observed: int64(100), Mode: drift.Updatable},
+					{Path: "volumeType", Desired: "hdd", Observed: "ssd", Mode: drift.Updatable},
+				},
+				status: ucansdk.VolumeStatusAvailable,
+			},
+			want: want{requests: []string{`POST /v3/project-1/volumes/vol-1/action volume 3.42 {"os-extend":{"new_size":200}}`}},
+		},
+		"Migrating": {
+			reason: "No request should be sent, and the volume should not be Synced, while it is being migrated.",
+			args: args{
+				mg:              volume(withProjectId("project-1"), withExternalName("vol-1")),
+				diff:            drift.Diff{{Path: "volumeType", Desired: "hdd", Observed: "ssd", Mode: drift.Updatable}},
+				status:          ucansdk.VolumeStatusInUse,
+				migrationStatus: ucansdk.MigrationStatusMigrating,
+			},
+			want: want{err: errors.New(errRetyping)},
+		},
+		"ExtendFailed": {
+			reason: "A volume that failed to extend should not be extended again.",
+			args: args{
+				mg:     volume(withProjectId("project-1"), withExternalName("vol-1"), withSize(200)),
+				diff:   drift.Diff{{Path: "size", Desired: int64(200), Observed: int64(100), Mode: drift.Updatable}},
+				status: ucansdk.VolumeStatusErrorExtending,
+			},
+			want: want{err: errors.New(errExtendFailed)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var requests []string
+			svc := newService(t, func(_ http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				requests = append(requests, r.Method+" "+r.URL.Path+" "+r.Header.Get("OpenStack-API-Version")+" "+string(body))
+			})
+			e := external{service: svc, logger: logging.NewNopLogger(), diff: tc.args.diff, status: tc.args.status, migrationStatus: tc.args.migrationStatus}
+			_, err := e.Update(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.requests, requests); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want requests, +got requests:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.retype, tc.args.mg.(*v1alpha1.Volume).Status.AtProvider.Retype); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want retype, +got retype:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestObserveRetype(t *testing.T) {
+	retyping := &v1alpha1.RetypeObservation{FromVolumeType: "ssd", ToVolumeType: "hdd", Phase: v1alpha1.RetypePhaseRetyping}
+
+	cases := map[string]struct {
+		reason     string
+		volumeType string
+		status     string
+		want       string
+	}{
+		"Retyping": {
+			reason:     "A volume that is being retyped should still be retyping.",
+			volumeType: "ssd",
+			status:     ucansdk.VolumeStatusRetyping,
+			want:       v1alpha1.RetypePhaseRetyping,
+		},
+		"Retyped": {
+			reason:     "A volume that settled on the requested type should be retyped.",
+			volumeType: "hdd",
+			status:     ucansdk.VolumeStatusAvailable,
+			want:       v1alpha1.RetypePhaseRetyped,
+		},
+		"Failed": {
+			reason:     "A volume that settled on another type should have failed to retype.",
+			volumeType: "ssd",
+			status:     ucansdk.VolumeStatusAvailable,
+			want:       v1alpha1.RetypePhaseFailed,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := *retyping
+			cr := volume(withAtProvider(v1alpha1.VolumeObservation{VolumeType: tc.volumeType}), withRetype(&r))
+			e := external{logger: logging.NewNopLogger(), status: tc.status}
+			e.observeRetype(cr)
+			if got := cr.Status.AtProvider.Retype.Phase; got != tc.want {
+				t.Errorf("\n%s\ne.observeRetype(...): want phase %q, got %q", tc.reason, tc.want, got)
+			}
 		})
 	}
 }
@@ -0,0 +1,302 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumeattachment
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/provider-ucan/apis/osgalaxy/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-ucan/apis/v1alpha1"
+	"github.com/crossplane/provider-ucan/internal/clients"
+	"github.com/crossplane/provider-ucan/internal/drift"
+	"github.com/crossplane/provider-ucan/internal/features"
+	"github.com/crossplane/provider-ucan/pkg/httpclient"
+	"github.com/crossplane/provider-ucan/pkg/ucansdk"
+)
+
+const (
+	errNotVolumeAttachment = "managed resource is not a VolumeAttachment custom resource"
+	errTrackPCUsage        = "cannot track ProviderConfig usage"
+	errNewClient           = "cannot create new Service"
+	errExternalName        = "external name %q is not of the form <server ID>/<volume ID>"
+	errGet                 = "cannot get volume attachment"
+	errAttach              = "cannot attach volume"
+	errDetach              = "cannot detach volume"
+	errUnmarshal           = "cannot unmarshal volume attachment"
+)
+
+type UcanClient struct {
+	*ucansdk.Client
+}
+
+// Setup adds a controller that reconciles VolumeAttachment managed resources.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1alpha1.VolumeAttachmentGroupKind)
+
+	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
+	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
+	}
+
+	log := o.Logger.WithValues("controller", name)
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnecter(&connector{
+			kube:    mgr.GetClient(),
+			usage:   resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			clients: clients.DefaultCache(),
+			logger:  log}),
+		// The external name of a VolumeAttachment is set by Create, so the
+		// default initializer must not default it to the object name.
+		managed.WithInitializers(),
+		managed.WithLogger(log),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithConnectionPublishers(cps...),
+	}
+	if o.Features.Enabled(features.EnableAlphaManagementPolicies) {
+		opts = append(opts, managed.WithManagementPolicies())
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1alpha1.VolumeAttachmentGroupVersionKind), opts...)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		WithEventFilter(resource.DesiredStateChanged()).
+		For(&v1alpha1.VolumeAttachment{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+type connector struct {
+	kube    client.Client
+	usage   resource.Tracker
+	clients *clients.Cache
+	logger  logging.Logger
+}
+
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	cr, ok := mg.(*v1alpha1.VolumeAttachment)
+	if !ok {
+		return nil, errors.New(errNotVolumeAttachment)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	svc, err := c.clients.Get(ctx, c.kube, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+
+	log := c.logger.WithValues("name", cr.GetName(), "externalID", meta.GetExternalName(cr))
+	return &external{service: &UcanClient{Client: svc.WithOptions(httpclient.WithLogger(log))}, logger: log}, nil
+}
+
+type external struct {
+	service *UcanClient
+	logger  logging.Logger
+
+	// diff is the drift found by the last call to Observe.
+	diff drift.Diff
+}
+
+// The external name of a VolumeAttachment is <server ID>/<volume ID>, since
+// UCAN identifies an attachment by both.
+func externalName(serverID, volumeID string) string {
+	return serverID + "/" + volumeID
+}
+
+func parseExternalName(name string) (serverID, volumeID string, err error) {
+	serverID, volumeID, ok := strings.Cut(name, "/")
+	if !ok || serverID == "" || volumeID == "" {
+		return "", "", errors.Errorf(errExternalName, name)
+	}
+	return serverID, volumeID, nil
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.VolumeAttachment)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotVolumeAttachment)
+	}
+
+	name := meta.GetExternalName(cr)
+	if name == "" {
+		c.logger.Debug("External resource has no ID")
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	serverID, volumeID, err := parseExternalName(name)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	attachment, err := ucansdk.GetVolumeAttachment(ctx, c.service.Client, serverID, volumeID)
+	if ucansdk.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		c.logger.Debug("Cannot get volume attachment", "error", err)
+		return managed.ExternalObservation{}, errors.Wrap(err, errGet)
+	}
+
+	var response ucansdk.VolumeAttachmentResp
+	if err = json.Unmarshal(attachment, &response); err != nil {
+		c.logger.Debug("Cannot unmarshal volume attachment", "error", err)
+		return managed.ExternalObservation{}, errors.Wrap(err, errUnmarshal)
+	}
+	c.logger.Debug("Observed volume attachment", "device", response.VolumeAttachment.Device)
+	cr.Status.AtProvider = generateObservation(response)
+	cr.SetConditions(xpv1.Available())
+
+	c.diff = generateDiff(cr, response)
+	if len(c.diff) > 0 {
+		c.logger.Debug("Observed drift", "diff", c.diff.String())
+	}
+	return drift.Observation(cr, c.diff)
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.VolumeAttachment)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotVolumeAttachment)
+	}
+
+	p := cr.Spec.ForProvider
+	reqData, err := json.Marshal(ucansdk.AttachVolumeReq{VolumeAttachment: ucansdk.AttachVolumeParams{
+		VolumeID: p.VolumeID,
+		Device:   p.Device,
+	}})
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errAttach)
+	}
+	attachment, err := ucansdk.AttachVolume(ctx, c.service.Client, p.ServerID, reqData)
+	if err != nil {
+		c.logger.Debug("Cannot attach volume", "error", err)
+		return managed.ExternalCreation{}, errors.Wrap(err, errAttach)
+	}
+
+	var response ucansdk.VolumeAttachmentResp
+	if err = json.Unmarshal(attachment, &response); err != nil {
+		c.logger.Debug("Cannot unmarshal volume attachment", "error", err)
+		return managed.ExternalCreation{}, errors.Wrap(err, errUnmarshal)
+	}
+	c.logger.Debug("Attached volume", "device", response.VolumeAttachment.Device)
+
+	meta.SetExternalName(cr, externalName(p.ServerID, p.VolumeID))
+	return managed.ExternalCreation{}, nil
+}
+
+// Update moves the volume attachment to another server or volume. The current
+// volume is detached, and the desired volume is attached by Create once UCAN
+// no longer reports the current attachment.
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.VolumeAttachment)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotVolumeAttachment)
+	}
+
+	diff := c.diff.Updatable()
+	if len(diff) == 0 {
+		return managed.ExternalUpdate{}, nil
+	}
+	c.logger.Debug("Moving volume attachment", "diff", diff.String())
+	return managed.ExternalUpdate{}, c.detach(ctx, meta.GetExternalName(cr))
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	cr, ok := mg.(*v1alpha1.VolumeAttachment)
+	if !ok {
+		return managed.ExternalDelete{}, errors.New(errNotVolumeAttachment)
+	}
+
+	name := meta.GetExternalName(cr)
+	if name == "" {
+		c.logger.Debug("External resource has no ID")
+		return managed.ExternalDelete{}, nil
+	}
+	return managed.ExternalDelete{}, c.detach(ctx, name)
+}
+
+// detach requests the detachment of the volume attachment with the supplied
+// external name. Detaching is asynchronous, so a volume that is already being
+// detached is not an error.
+func (c *external) detach(ctx context.Context, name string) error {
+	serverID, volumeID, err := parseExternalName(name)
+	if err != nil {
+		return err
+	}
+	_, err = ucansdk.DetachVolume(ctx, c.service.Client, serverID, volumeID)
+	switch {
+	case ucansdk.IsNotFound(err):
+		c.logger.Debug("Volume is already detached")
+		return nil
+	case ucansdk.IsConflict(err):
+		c.logger.Debug("Volume is being detached", "error", err)
+		return nil
+	case err != nil:
+		c.logger.Debug("Cannot detach volume", "error", err)
+		return errors.Wrap(err, errDetach)
+	}
+	c.logger.Debug("Requested detach of volume")
+	return nil
+}
+
+func (c *external) Disconnect(ctx context.Context) error {
+	return nil
+}
+
+// generateObservation projects the UCAN volume attachment onto the observable
+// fields of a VolumeAttachment.
+func generateObservation(resp ucansdk.VolumeAttachmentResp) v1alpha1.VolumeAttachmentObservation {
+	a := resp.VolumeAttachment
+	return v1alpha1.VolumeAttachmentObservation{
+		ID:       a.ID,
+		ServerID: a.ServerID,
+		VolumeID: a.VolumeID,
+		Device:   a.Device,
+	}
+}
+
+// generateDiff compares the parameters of a VolumeAttachment with the UCAN
+// volume attachment. The requested device path is not compared, since UCAN
+// may not honor it.
+func generateDiff(cr *v1alpha1.VolumeAttachment, resp ucansdk.VolumeAttachmentResp) drift.Diff {
+	p := cr.Spec.ForProvider
+	a := resp.VolumeAttachment
+
+	var d drift.Diff
+	drift.Compare(&d, "serverId", p.ServerID, a.ServerID, drift.Updatable)
+	drift.Compare(&d, "volumeId", p.VolumeID, a.VolumeID, drift.Updatable)
+	return d
+}
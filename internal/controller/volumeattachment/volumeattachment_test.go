@@ -0,0 +1,411 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumeattachment
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-ucan/apis/osgalaxy/v1alpha1"
+	"github.com/crossplane/provider-ucan/pkg/httpclient"
+	"github.com/crossplane/provider-ucan/pkg/ucansdk"
+)
+
+// Unlike many Kubernetes projects Crossplane does not use third party testing
+// libraries, per the common Go test review comments. Crossplane encourages the
+// use of table driven unit tests. The tests of the crossplane-runtime project
+// are representative of the testing style Crossplane encourages.
+//
+// https://github.com/golang/go/wiki/TestComments
+// https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
+
+const attachmentJSON = `{"volumeAttachment": {
+	"id": "vol-1",
+	"serverId": "vm-1",
+	"volumeId": "vol-1",
+	"device": "/dev/vdb"
+}}`
+
+var attachmentObservation = v1alpha1.VolumeAttachmentObservation{
+	ID:       "vol-1",
+	ServerID: "vm-1",
+	VolumeID: "vol-1",
+	Device:   "/dev/vdb",
+}
+
+type vaModifier func(*v1alpha1.VolumeAttachment)
+
+func withExternalName(n string) vaModifier {
+	return func(cr *v1alpha1.VolumeAttachment) { meta.SetExternalName(cr, n) }
+}
+
+func withParameters(p v1alpha1.VolumeAttachmentParameters) vaModifier {
+	return func(cr *v1alpha1.VolumeAttachment) { cr.Spec.ForProvider = p }
+}
+
+func withAtProvider(o v1alpha1.VolumeAttachmentObservation) vaModifier {
+	return func(cr *v1alpha1.VolumeAttachment) { cr.Status.AtProvider = o }
+}
+
+func withConditions(c ...xpv1.Condition) vaModifier {
+	return func(cr *v1alpha1.VolumeAttachment) { cr.SetConditions(c...) }
+}
+
+func volumeAttachment(m ...vaModifier) *v1alpha1.VolumeAttachment {
+	cr := &v1alpha1.VolumeAttachment{}
+	for _, f := range m {
+		f(cr)
+	}
+	return cr
+}
+
+// newService returns a UcanClient whose endpoints are served by h.
+func newService(t *testing.T, h http.HandlerFunc) *UcanClient {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	ep := ucansdk.Endpoint{BaseURL: srv.URL}
+	cli := httpclient.NewHttpClient(httpclient.SignCertificate{AccessKeyID: "ak", SecretAccessKey: "sk"}, httpclient.WithRetryPolicy(httpclient.RetryPolicy{MaxAttempts: 1}))
+	return &UcanClient{Client: ucansdk.NewClient(cli, ucansdk.Endpoints{VirtualMachine: ep, Volume: ep, Network: ep})}
+}
+
+func TestObserve(t *testing.T) {
+	params := v1alpha1.VolumeAttachmentParameters{ServerID: "vm-1", VolumeID: "vol-1"}
+	moved := v1alpha1.VolumeAttachmentParameters{ServerID: "vm-2", VolumeID: "vol-1"}
+
+	type want struct {
+		mg  resource.Managed
+		o   managed.ExternalObservation
+		err error
+	}
+
+	cases := map[string]struct {
+		reason  string
+		handler http.HandlerFunc
+		mg      resource.Managed
+		want    want
+	}{
+		"NoExternalName": {
+			reason: "A VolumeAttachment without an external name should not exist.",
+			mg:     volumeAttachment(withParameters(params)),
+			want: want{
+				mg: volumeAttachment(withParameters(params)),
+				o:  managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"InvalidExternalName": {
+			reason: "An external name that does not identify a server and a volume should return an error.",
+			mg:     volumeAttachment(withParameters(params), withExternalName("vol-1")),
+			want: want{
+				mg:  volumeAttachment(withParameters(params), withExternalName("vol-1")),
+				err: errors.Errorf(errExternalName, "vol-1"),
+			},
+		},
+		"NotFound": {
+			reason: "A detached volume should not exist.",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			mg: volumeAttachment(withParameters(params), withExternalName("vm-1/vol-1")),
+			want: want{
+				mg: volumeAttachment(withParameters(params), withExternalName("vm-1/vol-1")),
+				o:  managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"Attached": {
+			reason: "An attached volume should have its device path reported in status.atProvider.",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet || r.URL.Path != "/v3/servers/vm-1/os-volume_attachments/vol-1" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				_, _ = w.Write([]byte(attachmentJSON))
+			},
+			mg: volumeAttachment(withParameters(params), withExternalName("vm-1/vol-1")),
+			want: want{
+				mg: volumeAttachment(withParameters(params), withExternalName("vm-1/vol-1"),
+					withAtProvider(attachmentObservation), withConditions(xpv1.Available())),
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"Moved": {
+			reason: "A volume that should be attached to another server should need an update.",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(attachmentJSON))
+			},
+			mg: volumeAttachment(withParameters(moved), withExternalName("vm-1/vol-1")),
+			want: want{
+				mg: volumeAttachment(withParameters(moved), withExternalName("vm-1/vol-1"),
+					withAtProvider(attachmentObservation), withConditions(xpv1.Available())),
+				o: managed.ExternalObservation{
+					ResourceExists: true,
+					Diff:           `spec.forProvider.serverId: desired "vm-2", observed "vm-1"`,
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{service: newService(t, tc.handler), logger: logging.NewNopLogger()}
+			got, err := e.Observe(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.mg, tc.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want managed resource, +got managed resource:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	var req string
+	svc := newService(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		req = r.Method + " " + r.URL.Path + " " + string(body)
+		_, _ = w.Write([]byte(attachmentJSON))
+	})
+	e := external{service: svc, logger: logging.NewNopLogger()}
+	cr := volumeAttachment(withParameters(v1alpha1.VolumeAttachmentParameters{ServerID: "vm-1", VolumeID: "vol-1", Device: "/dev/vdb"}))
+
+	if _, err := e.Create(context.Background(), cr); err != nil {
+		t.Fatalf("e.Create(...): %v", err)
+	}
+	if want := `POST /v3/servers/vm-1/os-volume_attachments {"volumeAttachment":{"volumeId":"vol-1","device":"/dev/vdb"}}`; req != want {
+		t.Errorf("e.Create(...): want request %s, got %s", want, req)
+	}
+	if n := meta.GetExternalName(cr); n != "vm-1/vol-1" {
+		t.Errorf("e.Create(...): want external name %q, got %q", "vm-1/vol-1", n)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		status   int
+		mg       resource.Managed
+		requests []string
+		err      error
+	}{
+		"Detach": {
+			reason:   "Deleting a VolumeAttachment should detach the volume.",
+			mg:       volumeAttachment(withExternalName("vm-1/vol-1")),
+			requests: []string{"DELETE /v3/servers/vm-1/os-volume_attachments/vol-1"},
+		},
+		"Detaching": {
+			reason:   "A volume that is already being detached should not be an error.",
+			status:   http.StatusConflict,
+			mg:       volumeAttachment(withExternalName("vm-1/vol-1")),
+			requests: []string{"DELETE /v3/servers/vm-1/os-volume_attachments/vol-1"},
+		},
+		"DetachError": {
+			reason:   "Errors detaching the volume should be returned.",
+			status:   http.StatusForbidden,
+			mg:       volumeAttachment(withExternalName("vm-1/vol-1")),
+			requests: []string{"DELETE /v3/servers/vm-1/os-volume_attachments/vol-1"},
+			err:      errors.Wrap(&ucansdk.APIError{StatusCode: http.StatusForbidden}, errDetach),
+		},
+		"NoExternalName": {
+			reason: "A VolumeAttachment that was never attached should not send a request.",
+			mg:     volumeAttachment(),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var requests []string
+			svc := newService(t, func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.Path)
+				if tc.status != 0 {
+					w.WriteHeader(tc.status)
+				}
+			})
+			e := external{service: svc, logger: logging.NewNopLogger()}
+			_, err := e.Delete(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.requests, requests); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want requests, +got requests:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	var requests []string
+	svc := newService(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		_, _ = w.Write([]byte(attachmentJSON))
+	})
+	e := external{service: svc, logger: logging.NewNopLogger()}
+	cr := volumeAttachment(withParameters(v1alpha1.VolumeAttachmentParameters{ServerID: "vm-2", VolumeID: "vol-1"}), withExternalName("vm-1/vol-1"))
+
+	if _, err := e.Observe(context.Background(), cr); err != nil {
+		t.Fatalf("e.Observe(...): %v", err)
+	}
+	if _, err := e.Update(context.Background(), cr); err != nil {
+		t.Fatalf("e.Update(...): %v", err)
+	}
+	want := []string{
+		"GET /v3/servers/vm-1/os-volume_attachments/vol-1",
+		"DELETE /v3/servers/vm-1/os-volume_attachments/vol-1",
+	}
+	if diff := cmp.Diff(want, requests); diff != "" {
+		t.Errorf("e.Update(...): a volume that moved should be detached from its old server: -want requests, +got requests:\n%s", diff)
+	}
+}
+
+// apiServer stores a single VolumeAttachment the way the API server would:
+// updates do not change its status, and status updates change nothing else.
+type apiServer struct {
+	stored *v1alpha1.VolumeAttachment
+}
+
+func (s *apiServer) client() *test.MockClient {
+	return &test.MockClient{
+		MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			s.stored.DeepCopyInto(obj.(*v1alpha1.VolumeAttachment))
+			return nil
+		},
+		MockUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+			cr := obj.(*v1alpha1.VolumeAttachment).DeepCopy()
+			cr.Status = s.stored.Status
+			s.stored = cr
+			cr.DeepCopyInto(obj.(*v1alpha1.VolumeAttachment))
+			return nil
+		},
+		MockStatusUpdate: func(_ context.Context, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+			obj.(*v1alpha1.VolumeAttachment).Status.DeepCopyInto(&s.stored.Status)
+			return nil
+		},
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	var requests []string
+	svc := newService(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		_, _ = w.Write([]byte(attachmentJSON))
+	})
+
+	cr := volumeAttachment(withParameters(v1alpha1.VolumeAttachmentParameters{ServerID: "vm-1", VolumeID: "vol-1"}))
+	cr.SetName("data-volume")
+	s := &apiServer{stored: cr}
+
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.SchemeBuilder.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme(...): %v", err)
+	}
+	r := managed.NewReconciler(&fake.Manager{Client: s.client(), Scheme: scheme},
+		resource.ManagedKind(v1alpha1.VolumeAttachmentGroupVersionKind),
+		managed.WithExternalConnecter(managed.ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (managed.ExternalClient, error) {
+			return &external{service: svc, logger: logging.NewNopLogger()}, nil
+		})),
+		managed.WithInitializers(),
+	)
+
+	// The first reconcile attaches the volume, and the second observes it.
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "data-volume"}}
+	for i := 0; i < 2; i++ {
+		if _, err := r.Reconcile(context.Background(), req); err != nil {
+			t.Fatalf("r.Reconcile(...): %v", err)
+		}
+	}
+
+	want := []string{
+		"POST /v3/servers/vm-1/os-volume_attachments",
+		"GET /v3/servers/vm-1/os-volume_attachments/vol-1",
+	}
+	if diff := cmp.Diff(want, requests); diff != "" {
+		t.Errorf("r.Reconcile(...): a VolumeAttachment without an external name should be attached: -want requests, +got requests:\n%s", diff)
+	}
+	if n := meta.GetExternalName(s.stored); n != "vm-1/vol-1" {
+		t.Errorf("r.Reconcile(...): want external name %q, got %q", "vm-1/vol-1", n)
+	}
+	if diff := cmp.Diff(xpv1.Available(), s.stored.GetCondition(xpv1.TypeReady), test.EquateConditions()); diff != "" {
+		t.Errorf("r.Reconcile(...): -want ready condition, +got ready condition:\n%s", diff)
+	}
+}
+
+func TestMoveWithChangedRef(t *testing.T) {
+	// A reference that resolves Always follows the VirtualMachine it names.
+	always := xpv1.ResolvePolicyAlways
+	cr := volumeAttachment(withExternalName("vm-1/vol-1"), withParameters(v1alpha1.VolumeAttachmentParameters{
+		ServerID:  "vm-1",
+		ServerRef: &xpv1.Reference{Name: "web-2", Policy: &xpv1.Policy{Resolve: &always}},
+		VolumeID:  "vol-1",
+	}))
+	kube := &test.MockClient{MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+		vm := obj.(*v1alpha1.VirtualMachine)
+		meta.SetExternalName(vm, "vm-2")
+		vm.SetConditions(xpv1.Available())
+		return nil
+	}}
+	if err := cr.ResolveReferences(context.Background(), kube); err != nil {
+		t.Fatalf("cr.ResolveReferences(...): %v", err)
+	}
+	if cr.Spec.ForProvider.ServerID != "vm-2" {
+		t.Fatalf("cr.ResolveReferences(...): want serverId %q, got %q", "vm-2", cr.Spec.ForProvider.ServerID)
+	}
+
+	var requests []string
+	svc := newService(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		_, _ = w.Write([]byte(attachmentJSON))
+	})
+	e := external{service: svc, logger: logging.NewNopLogger()}
+	o, err := e.Observe(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("e.Observe(...): %v", err)
+	}
+	if o.ResourceUpToDate {
+		t.Fatal("e.Observe(...): want a VolumeAttachment whose server reference changed to need an update")
+	}
+	if _, err := e.Update(context.Background(), cr); err != nil {
+		t.Fatalf("e.Update(...): %v", err)
+	}
+	want := []string{
+		"GET /v3/servers/vm-1/os-volume_attachments/vol-1",
+		"DELETE /v3/servers/vm-1/os-volume_attachments/vol-1",
+	}
+	if diff := cmp.Diff(want, requests); diff != "" {
+		t.Errorf("e.Update(...): the volume should be detached from its old server: -want requests, +got requests:\n%s", diff)
+	}
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package drift compares the desired state of a managed resource with the
+// observed state of its UCAN resource.
+package drift
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const errImmutable = "cannot change fields in place"
+
+// A Mode describes whether UCAN can change a field in place.
+type Mode int
+
+// Modes of a field.
+const (
+	// Immutable fields can only be set when the resource is created.
+	Immutable Mode = iota
+
+	// Updatable fields can be changed in place by Update.
+	Updatable
+)
+
+// A Field of spec.forProvider whose desired value differs from the observed
+// value.
+type Field struct {
+	Path     string
+	Desired  any
+	Observed any
+	Mode     Mode
+}
+
+func (f Field) String() string {
+	return fmt.Sprintf("spec.forProvider.%s: desired %v, observed %v", f.Path, format(f.Desired), format(f.Observed))
+}
+
+func format(v any) string {
+	if s, ok := v.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// A Diff lists the fields that have drifted.
+type Diff []Field
+
+// Updatable returns the fields of the Diff that can be changed in place.
+func (d Diff) Updatable() Diff {
+	return d.filter(Updatable)
+}
+
+// Immutable returns the fields of the Diff that cannot be changed in place.
+func (d Diff) Immutable() Diff {
+	return d.filter(Immutable)
+}
+
+// Has returns true if the field at path has drifted.
+func (d Diff) Has(path string) bool {
+	return slices.ContainsFunc(d, func(f Field) bool { return f.Path == path })
+}
+
+func (d Diff) filter(m Mode) Diff {
+	var out Diff
+	for _, f := range d {
+		if f.Mode == m {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func (d Diff) String() string {
+	s := make([]string, len(d))
+	for i, f := range d {
+		s[i] = f.String()
+	}
+	return strings.Join(s, "; ")
+}
+
+// Compare records a Field if desired is set and differs from observed. Fields
+// that are not set in the spec are not managed, and never drift.
+func Compare[T comparable](d *Diff, path string, desired, observed T, m Mode) {
+	var zero T
+	if desired == zero || desired == observed {
+		return
+	}
+	*d = append(*d, Field{Path: path, Desired: desired, Observed: observed, Mode: m})
+}
+
+// ComparePtr records a Field if desired is set and differs from observed.
+// Unlike Compare, a desired zero value is compared, so that a field can be
+// cleared by setting it explicitly.
+func ComparePtr[T comparable](d *Diff, path string, desired *T, observed T, m Mode) {
+	if desired == nil || *desired == observed {
+		return
+	}
+	*d = append(*d, Field{Path: path, Desired: *desired, Observed: observed, Mode: m})
+}
+
+// CompareMap records a Field if any entry of desired is missing from or
+// differs in observed. Entries UCAN adds to observed are ignored.
+func CompareMap(d *Diff, path string, desired, observed map[string]string, m Mode) {
+	for k, v := range desired {
+		if ov, ok := observed[k]; !ok || ov != v {
+			*d = append(*d, Field{Path: path, Desired: desired, Observed: observed, Mode: m})
+			return
+		}
+	}
+}
+
+// CompareSet records a Field if desired is set and does not hold the same
+// elements as observed, in any order.
+func CompareSet(d *Diff, path string, desired, observed []string, m Mode) {
+	if len(desired) == 0 {
+		return
+	}
+	want := slices.Sorted(slices.Values(desired))
+	got := slices.Sorted(slices.Values(observed))
+	if slices.Equal(slices.Compact(want), slices.Compact(got)) {
+		return
+	}
+	*d = append(*d, Field{Path: path, Desired: desired, Observed: observed, Mode: m})
+}
+
+// Observation returns an ExternalObservation of an existing resource with the
+// supplied Diff. The resource is up to date unless an updatable field has
+// drifted. Drift of immutable fields is returned as an error, which is
+// reported in the Synced condition of mg, unless mg is being deleted or its
+// management policies do not allow updates.
+func Observation(mg resource.Managed, d Diff) (managed.ExternalObservation, error) {
+	if imm := d.Immutable(); len(imm) > 0 && !meta.WasDeleted(mg) && updatesAllowed(mg) {
+		return managed.ExternalObservation{}, errors.New(errImmutable + ": " + imm.String())
+	}
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: len(d.Updatable()) == 0,
+		Diff:             d.String(),
+	}, nil
+}
+
+func updatesAllowed(mg resource.Managed) bool {
+	p := mg.GetManagementPolicies()
+	return len(p) == 0 || slices.Contains(p, xpv1.ManagementActionAll) || slices.Contains(p, xpv1.ManagementActionUpdate)
+}
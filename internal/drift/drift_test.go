@@ -0,0 +1,129 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drift
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestCompare(t *testing.T) {
+	var d Diff
+	Compare(&d, "name", "", "web", Updatable)
+	Compare(&d, "size", int64(10), int64(10), Updatable)
+	CompareMap(&d, "metadata", map[string]string{"team": "web"}, map[string]string{"team": "web", "ucan": "added"}, Updatable)
+	CompareSet(&d, "securityGroups", []string{"b", "a"}, []string{"a", "b"}, Immutable)
+	ComparePtr(&d, "description", nil, "imported", Updatable)
+	if len(d) != 0 {
+		t.Errorf("Compare(...): want no drift, got %s", d)
+	}
+
+	Compare(&d, "size", int64(20), int64(10), Updatable)
+	CompareMap(&d, "metadata", map[string]string{"team": "api"}, map[string]string{"team": "web"}, Updatable)
+	CompareSet(&d, "securityGroups", []string{"a"}, []string{"a", "b"}, Immutable)
+	ComparePtr(&d, "description", new(string), "imported", Updatable)
+	want := Diff{
+		{Path: "size", Desired: int64(20), Observed: int64(10), Mode: Updatable},
+		{Path: "metadata", Desired: map[string]string{"team": "api"}, Observed: map[string]string{"team": "web"}, Mode: Updatable},
+		{Path: "securityGroups", Desired: []string{"a"}, Observed: []string{"a", "b"}, Mode: Immutable},
+		{Path: "description", Desired: "", Observed: "imported", Mode: Updatable},
+	}
+	if diff := cmp.Diff(want, d); diff != "" {
+		t.Errorf("Compare(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestObservation(t *testing.T) {
+	updatable := Diff{{Path: "name", Desired: "api", Observed: "web", Mode: Updatable}}
+	immutable := Diff{{Path: "imageRef", Desired: "image-2", Observed: "image-1", Mode: Immutable}}
+	now := metav1.Now()
+
+	type want struct {
+		o   managed.ExternalObservation
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		mg     *fake.Managed
+		d      Diff
+		want   want
+	}{
+		"UpToDate": {
+			reason: "A resource without drift should be up to date.",
+			mg:     &fake.Managed{},
+			want:   want{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}},
+		},
+		"Updatable": {
+			reason: "A resource with updatable drift should need an update.",
+			mg:     &fake.Managed{},
+			d:      updatable,
+			want: want{o: managed.ExternalObservation{
+				ResourceExists: true,
+				Diff:           `spec.forProvider.name: desired "api", observed "web"`,
+			}},
+		},
+		"Immutable": {
+			reason: "A resource with immutable drift should return an error.",
+			mg:     &fake.Managed{},
+			d:      immutable,
+			want:   want{err: errors.New(`cannot change fields in place: spec.forProvider.imageRef: desired "image-2", observed "image-1"`)},
+		},
+		"ImmutableDeleted": {
+			reason: "Immutable drift should not block the deletion of a resource.",
+			mg:     &fake.Managed{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now}},
+			d:      immutable,
+			want: want{o: managed.ExternalObservation{
+				ResourceExists:   true,
+				ResourceUpToDate: true,
+				Diff:             `spec.forProvider.imageRef: desired "image-2", observed "image-1"`,
+			}},
+		},
+		"ImmutableObserveOnly": {
+			reason: "Immutable drift should not be an error if updates are not allowed.",
+			mg: &fake.Managed{Manageable: fake.Manageable{
+				Policy: xpv1.ManagementPolicies{xpv1.ManagementActionObserve, xpv1.ManagementActionDelete},
+			}},
+			d: immutable,
+			want: want{o: managed.ExternalObservation{
+				ResourceExists:   true,
+				ResourceUpToDate: true,
+				Diff:             `spec.forProvider.imageRef: desired "image-2", observed "image-1"`,
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Observation(tc.mg, tc.d)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nObservation(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\nObservation(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
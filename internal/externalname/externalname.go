@@ -0,0 +1,59 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package externalname migrates UCAN IDs stored by earlier provider versions
+// to the crossplane.io/external-name annotation.
+package externalname
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const errUpdateManaged = "cannot update managed resource"
+
+// A LegacyAnnotationMigrator moves the UCAN ID from a legacy annotation, such
+// as ucan.io/virtualmachine-uuid, to the external-name annotation.
+type LegacyAnnotationMigrator struct {
+	client client.Client
+	key    string
+}
+
+// NewLegacyAnnotationMigrator returns an initializer that migrates the
+// supplied legacy annotation key.
+func NewLegacyAnnotationMigrator(c client.Client, key string) *LegacyAnnotationMigrator {
+	return &LegacyAnnotationMigrator{client: c, key: key}
+}
+
+// Initialize sets the external name of mg to the value of the legacy
+// annotation, if present, and removes the legacy annotation. Earlier provider
+// versions set the external name to the name of the managed resource, so the
+// legacy annotation takes precedence over any existing external name.
+func (m *LegacyAnnotationMigrator) Initialize(ctx context.Context, mg resource.Managed) error {
+	id, ok := mg.GetAnnotations()[m.key]
+	if !ok {
+		return nil
+	}
+	if id != "" {
+		meta.SetExternalName(mg, id)
+	}
+	meta.RemoveAnnotations(mg, m.key)
+	return errors.Wrap(m.client.Update(ctx, mg), errUpdateManaged)
+}
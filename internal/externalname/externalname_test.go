@@ -0,0 +1,99 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalname
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const legacyKey = "ucan.io/volume-uuid"
+
+func TestInitialize(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		annotations map[string]string
+		updated     bool
+		err         error
+	}
+
+	cases := map[string]struct {
+		reason      string
+		annotations map[string]string
+		updateErr   error
+		want        want
+	}{
+		"NoLegacyAnnotation": {
+			reason:      "Resources without the legacy annotation should not be updated.",
+			annotations: map[string]string{meta.AnnotationKeyExternalName: "uuid"},
+			want:        want{annotations: map[string]string{meta.AnnotationKeyExternalName: "uuid"}},
+		},
+		"Migrate": {
+			reason: "The legacy ID should replace an external name that was defaulted to the resource name.",
+			annotations: map[string]string{
+				meta.AnnotationKeyExternalName: "my-volume",
+				legacyKey:                      "uuid",
+				"team":                         "storage",
+			},
+			want: want{
+				annotations: map[string]string{meta.AnnotationKeyExternalName: "uuid", "team": "storage"},
+				updated:     true,
+			},
+		},
+		"UpdateError": {
+			reason:      "Errors updating the resource should be returned.",
+			annotations: map[string]string{legacyKey: "uuid"},
+			updateErr:   errBoom,
+			want: want{
+				annotations: map[string]string{meta.AnnotationKeyExternalName: "uuid"},
+				updated:     true,
+				err:         errors.Wrap(errBoom, errUpdateManaged),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			updated := false
+			kube := &test.MockClient{MockUpdate: func(_ context.Context, _ client.Object, _ ...client.UpdateOption) error {
+				updated = true
+				return tc.updateErr
+			}}
+			mg := &fake.Managed{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+
+			err := NewLegacyAnnotationMigrator(kube, legacyKey).Initialize(context.Background(), mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nInitialize(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.annotations, mg.GetAnnotations()); diff != "" {
+				t.Errorf("\n%s\nInitialize(...): -want annotations, +got annotations:\n%s\n", tc.reason, diff)
+			}
+			if updated != tc.want.updated {
+				t.Errorf("\n%s\nInitialize(...): want updated %t, got %t", tc.reason, tc.want.updated, updated)
+			}
+		})
+	}
+}
@@ -21,7 +21,7 @@ type SignCertificate struct {
 	Region          string `json:"region"`
 }
 
-func Sign(request *http.Request, certificate SignCertificate) error {
+func Sign(ctx context.Context, request *http.Request, certificate SignCertificate) error {
 	if certificate.AccessKeyID == "" || certificate.SecretAccessKey == "" {
 		return fmt.Errorf("invalid certificate")
 	}
@@ -48,7 +48,7 @@ func Sign(request *http.Request, certificate SignCertificate) error {
 		opts.DisableURIPathEscaping = true
 	})
 
-	return s.SignHTTP(context.Background(), cred, request, payloadHash, certificate.Service, certificate.Region, time.Now())
+	return s.SignHTTP(ctx, cred, request, payloadHash, certificate.Service, certificate.Region, time.Now())
 }
 
 // GetPayloadHash 计算 Payload Hash
@@ -2,49 +2,42 @@ package httpclient
 
 import (
 	"bytes"
-	"fmt"
+	"context"
 	"io"
 	"net/http"
-	"time"
 )
 
+// HttpClient sends SigV4 signed requests. It is not modified after
+// construction and is safe for concurrent use; per-request headers and query
+// parameters are supplied as RequestOptions.
 type HttpClient struct {
 	signCertificate SignCertificate
-	header          map[string]string
 	httpCli         *http.Client
-	query           map[string]string
-	timeout         time.Duration // http请求超时
+	defaults        []RequestOption
 }
 
-func NewHttpClient(signCertificate SignCertificate) *HttpClient {
+// NewHttpClient returns a client that signs requests with signCertificate.
+// Connections are pooled in a transport shared by all clients. The supplied
+// options are applied to every request before any per-request
+// options.
+func NewHttpClient(signCertificate SignCertificate, defaults ...RequestOption) *HttpClient {
 	return &HttpClient{
 		signCertificate: signCertificate,
-		httpCli:         &http.Client{},
-		header:          make(map[string]string),
-		query:           make(map[string]string),
-		timeout:         0, // 默认超时时间为0，表示没有设置超时
+		httpCli:         &http.Client{Transport: sharedTransport()},
+		defaults:        defaults,
 	}
 }
 
-// SetTimeout 设置超时时间
-func (client *HttpClient) SetTimeout(timeout time.Duration) {
-	client.timeout = timeout
-	client.httpCli.Timeout = timeout
+func (client *HttpClient) GET(ctx context.Context, url string, data []byte, opts ...RequestOption) ([]byte, int, error) {
+	return client.Request(ctx, url, http.MethodGet, data, opts...)
 }
 
-func (client *HttpClient) GET(url string, data []byte) ([]byte, int, error) {
-	return client.Request(url, http.MethodGet, data)
-}
-
-func (client *HttpClient) RawGET(url string, data []byte) (*http.Response, error) {
-	req, err := http.NewRequest("GET", url, bytes.NewReader(data))
+func (client *HttpClient) RawGET(ctx context.Context, url string, data []byte, opts ...RequestOption) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, bytes.NewReader(data))
 	if err != nil {
 		return nil, err
 	}
-	// 设置请求头
-	for key, value := range client.header {
-		req.Header.Set(key, value)
-	}
+	newRequestOptions(client.defaults, opts).apply(req)
 	resp, err := client.httpCli.Do(req)
 	if err != nil {
 		return nil, err
@@ -52,62 +45,97 @@ func (client *HttpClient) RawGET(url string, data []byte) (*http.Response, error
 	return resp, nil
 }
 
-func (client *HttpClient) POST(url string, data []byte) ([]byte, int, error) {
-	return client.Request(url, http.MethodPost, data)
+func (client *HttpClient) POST(ctx context.Context, url string, data []byte, opts ...RequestOption) ([]byte, int, error) {
+	return client.Request(ctx, url, http.MethodPost, data, opts...)
 }
 
-func (client *HttpClient) DELETE(url string, data []byte) ([]byte, int, error) {
-	return client.Request(url, http.MethodDelete, data)
+func (client *HttpClient) DELETE(ctx context.Context, url string, data []byte, opts ...RequestOption) ([]byte, int, error) {
+	return client.Request(ctx, url, http.MethodDelete, data, opts...)
 }
 
-func (client *HttpClient) PUT(url string, data []byte) ([]byte, int, error) {
-	return client.Request(url, http.MethodPut, data)
+func (client *HttpClient) PUT(ctx context.Context, url string, data []byte, opts ...RequestOption) ([]byte, int, error) {
+	return client.Request(ctx, url, http.MethodPut, data, opts...)
 }
 
-func (client *HttpClient) SetHeader(key, value string) {
-	client.header[key] = value
+// A Response is a completely read HTTP response.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Request sends a signed request and returns the response body and status
+// code. The request is cancelled when ctx is done.
+func (client *HttpClient) Request(ctx context.Context, url, method string, data []byte, opts ...RequestOption) ([]byte, int, error) {
+	rsp, err := client.Do(ctx, method, url, data, opts...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return rsp.Body, rsp.StatusCode, nil
 }
 
-func (client *HttpClient) SetQuery(key, value string) {
-	client.query[key] = value
+// Do sends a signed request and returns the read response. Requests that
+// fail transiently are retried according to the RetryPolicy of the request;
+// POST and PATCH requests are only retried when WithRetryNonIdempotent is
+// supplied. The request is cancelled when ctx is done.
+func (client *HttpClient) Do(ctx context.Context, method, url string, data []byte, opts ...RequestOption) (*Response, error) {
+	o := newRequestOptions(client.defaults, opts)
+	retry := idempotent(method) || o.retryNonIdempotent
+
+	for attempt := 1; ; attempt++ {
+		rsp, err := client.do(ctx, method, url, data, o)
+		if err != nil {
+			o.log.Debug("UCAN request failed", "method", method, "url", url, "attempt", attempt, "error", err)
+		} else {
+			o.log.Debug("UCAN request", "method", method, "url", url, "attempt", attempt, "status", rsp.StatusCode)
+		}
+		if !retry || ctx.Err() != nil || (err == nil && rsp.StatusCode < http.StatusBadRequest) || (err != nil && !transient(err)) {
+			return rsp, err
+		}
+		delay, ok := o.retry.retryDelay(attempt, rsp)
+		if !ok {
+			return rsp, err
+		}
+		o.log.Debug("Retrying UCAN request", "method", method, "url", url, "delay", delay)
+		if serr := sleep(ctx, delay); serr != nil {
+			if err != nil {
+				return nil, err
+			}
+			return rsp, nil
+		}
+	}
 }
 
-func (client *HttpClient) Request(url, method string, data []byte) ([]byte, int, error) {
+// do makes a single signed attempt of a request.
+func (client *HttpClient) do(ctx context.Context, method, url string, data []byte, o *requestOptions) (*Response, error) {
 	var req *http.Request
 	var errReq error
 	if data != nil {
-		req, errReq = http.NewRequest(method, url, bytes.NewReader(data))
+		req, errReq = http.NewRequestWithContext(ctx, method, url, bytes.NewReader(data))
 	} else {
-		req, errReq = http.NewRequest(method, url, nil)
+		req, errReq = http.NewRequestWithContext(ctx, method, url, nil)
 	}
 	if errReq != nil {
-		return nil, 0, errReq
-	}
-	req.Close = true
-	for key, value := range client.header {
-		req.Header.Set(key, value)
-	}
-	if method == http.MethodGet {
-		q := req.URL.Query()
-		for k, v := range client.query {
-			q.Add(k, v)
-		}
-		req.URL.RawQuery = q.Encode()
+		return nil, errReq
 	}
+	o.apply(req)
 
-	if err := Sign(req, client.signCertificate); err != nil {
-		return nil, 0, err
+	if err := Sign(ctx, req, client.signCertificate); err != nil {
+		return nil, err
 	}
 
 	rsp, err := client.httpCli.Do(req)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	defer func() {
-		if err = rsp.Body.Close(); err != nil {
-			fmt.Printf("failed to close response body: %v", err)
+		if err := rsp.Body.Close(); err != nil {
+			o.log.Debug("Cannot close response body", "error", err)
 		}
 	}()
 	body, err := io.ReadAll(rsp.Body)
-	return body, rsp.StatusCode, err
+	if err != nil {
+		return nil, err
+	}
+	return &Response{StatusCode: rsp.StatusCode, Header: rsp.Header, Body: body}, nil
 }
@@ -0,0 +1,88 @@
+package httpclient
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func TestRequestContextCancelled(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	cli := NewHttpClient(SignCertificate{AccessKeyID: "ak", SecretAccessKey: "sk", Service: "ucan", Region: "RegionOne"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, _, err := cli.GET(ctx, srv.URL, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GET(...): want context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestRequestOptionsDoNotLeak(t *testing.T) {
+	type seen struct {
+		ns    string
+		query string
+		ct    string
+	}
+	var got []seen
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, seen{ns: r.Header.Get(NamespaceHeader), query: r.URL.RawQuery, ct: r.Header.Get("Content-Type")})
+	}))
+	defer srv.Close()
+
+	cli := NewHttpClient(SignCertificate{AccessKeyID: "ak", SecretAccessKey: "sk"}, WithHeader("Content-Type", "application/json"))
+
+	if _, _, err := cli.GET(context.Background(), srv.URL, nil, WithNamespace("project-a"), WithQuery("limit", "1")); err != nil {
+		t.Fatalf("GET(...): %v", err)
+	}
+	if _, _, err := cli.GET(context.Background(), srv.URL, nil); err != nil {
+		t.Fatalf("GET(...): %v", err)
+	}
+
+	want := []seen{
+		{ns: "project-a", query: "limit=1", ct: "application/json"},
+		{ct: "application/json"},
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(seen{})); diff != "" {
+		t.Errorf("GET(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestRequestReusesConnections(t *testing.T) {
+	var conns atomic.Int32
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.Config.ConnState = func(_ net.Conn, s http.ConnState) {
+		if s == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	cli := NewHttpClient(SignCertificate{AccessKeyID: "ak", SecretAccessKey: "sk"})
+	for range 3 {
+		if _, _, err := cli.GET(context.Background(), srv.URL, nil); err != nil {
+			t.Fatalf("GET(...): %v", err)
+		}
+	}
+	if n := conns.Load(); n != 1 {
+		t.Errorf("GET(...): want 1 connection for 3 requests, got %d", n)
+	}
+}
@@ -0,0 +1,78 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+// NamespaceHeader carries the UCAN project a request is scoped to.
+const NamespaceHeader = "X-UCAN-NS"
+
+// A RequestOption customizes a single request. Options never modify the
+// HttpClient, so one client may be shared by concurrent callers.
+type RequestOption func(o *requestOptions)
+
+type requestOptions struct {
+	header             http.Header
+	query              url.Values
+	retry              RetryPolicy
+	retryNonIdempotent bool
+	log                logging.Logger
+}
+
+func newRequestOptions(opts ...[]RequestOption) *requestOptions {
+	o := &requestOptions{header: http.Header{}, query: url.Values{}, retry: DefaultRetryPolicy(), log: logging.NewNopLogger()}
+	for _, set := range opts {
+		for _, fn := range set {
+			fn(o)
+		}
+	}
+	return o
+}
+
+// WithHeader sets a request header, replacing any earlier value for key.
+func WithHeader(key, value string) RequestOption {
+	return func(o *requestOptions) {
+		o.header.Set(key, value)
+	}
+}
+
+// WithQuery adds a query parameter to the request URL.
+func WithQuery(key, value string) RequestOption {
+	return func(o *requestOptions) {
+		o.query.Add(key, value)
+	}
+}
+
+// WithNamespace scopes the request to the supplied UCAN project.
+func WithNamespace(projectID string) RequestOption {
+	return WithHeader(NamespaceHeader, projectID)
+}
+
+// WithLogger logs the request, its retries and its response status to log at
+// debug level.
+func WithLogger(log logging.Logger) RequestOption {
+	return func(o *requestOptions) {
+		o.log = log
+	}
+}
+
+// apply copies the options onto req.
+func (o *requestOptions) apply(req *http.Request) {
+	for key, values := range o.header {
+		for _, v := range values {
+			req.Header.Add(key, v)
+		}
+	}
+	if len(o.query) > 0 {
+		q := req.URL.Query()
+		for key, values := range o.query {
+			for _, v := range values {
+				q.Add(key, v)
+			}
+		}
+		req.URL.RawQuery = q.Encode()
+	}
+}
@@ -0,0 +1,159 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// A RetryPolicy controls how requests that fail transiently are retried.
+// Every attempt is signed again, since SigV4 signatures are time-bound.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Values below 2 disable retries.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry. It doubles on every
+	// further retry.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between attempts. A Retry-After longer than
+	// MaxDelay is not waited for; the failed response is returned instead.
+	MaxDelay time.Duration
+	// RetryableStatus lists the response status codes that are retried.
+	RetryableStatus []int
+}
+
+// DefaultRetryPolicy returns the policy used by clients that do not configure
+// one.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   200 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+		RetryableStatus: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// WithRetryPolicy sets the retry policy of a request. Pass it to
+// NewHttpClient to change the policy of every request.
+func WithRetryPolicy(p RetryPolicy) RequestOption {
+	return func(o *requestOptions) {
+		o.retry = p
+	}
+}
+
+// WithRetryNonIdempotent allows a POST or PATCH request to be retried. Only
+// use it for requests the service deduplicates, since an attempt that failed
+// with a connection error may still have been processed.
+func WithRetryNonIdempotent() RequestOption {
+	return func(o *requestOptions) {
+		o.retryNonIdempotent = true
+	}
+}
+
+// sleep waits between attempts. It is a variable so that tests can skip the
+// wait.
+var sleep = sleepWithContext
+
+// sleepWithContext waits for d or until ctx is done.
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// transient returns true if the connection to the server was refused, reset
+// or timed out, or was closed before the response was read. Other errors, e.g.
+// a certificate that is not trusted, fail the same way on every attempt.
+func transient(err error) bool {
+	var nerr net.Error
+	switch {
+	case errors.Is(err, syscall.ECONNREFUSED), errors.Is(err, syscall.ECONNRESET):
+		return true
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return true
+	}
+	return errors.As(err, &nerr) && nerr.Timeout()
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryDelay returns how long to wait before attempt number attempt+1, and
+// whether another attempt should be made at all. rsp is nil when the previous
+// attempt failed without a response.
+func (p RetryPolicy) retryDelay(attempt int, rsp *Response) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts {
+		return 0, false
+	}
+	if rsp != nil {
+		if !p.retryable(rsp.StatusCode) {
+			return 0, false
+		}
+		if d, ok := retryAfter(rsp.Header.Get("Retry-After")); ok {
+			return d, d <= p.MaxDelay
+		}
+	}
+	return p.backoff(attempt), true
+}
+
+func (p RetryPolicy) retryable(code int) bool {
+	for _, c := range p.RetryableStatus {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns an exponential delay with jitter in [d/2, d).
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.BaseDelay << (attempt - 1)
+	if d <= 0 || (p.MaxDelay > 0 && d > p.MaxDelay) {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + rand.N(d-half) //nolint:gosec // Jitter does not need a secure source.
+}
+
+// retryAfter parses a Retry-After header in either of its delta-seconds or
+// HTTP-date forms.
+func retryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if s, err := strconv.Atoi(v); err == nil && s >= 0 {
+		return time.Duration(s) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
@@ -0,0 +1,150 @@
+package httpclient
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDoRetry(t *testing.T) {
+	sleep = func(_ context.Context, _ time.Duration) error { return nil }
+	defer func() { sleep = sleepWithContext }()
+
+	type want struct {
+		attempts int
+		status   int
+	}
+
+	cases := map[string]struct {
+		reason string
+		method string
+		opts   []RequestOption
+		status []int
+		header http.Header
+		tls    bool
+		want   want
+	}{
+		"RetryTransient": {
+			reason: "Idempotent requests should be retried until they succeed.",
+			method: http.MethodGet,
+			status: []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK},
+			want:   want{attempts: 3, status: http.StatusOK},
+		},
+		"GiveUp": {
+			reason: "The last response should be returned once MaxAttempts is reached.",
+			method: http.MethodDelete,
+			status: []int{http.StatusGatewayTimeout, http.StatusGatewayTimeout, http.StatusGatewayTimeout, http.StatusOK},
+			want:   want{attempts: 3, status: http.StatusGatewayTimeout},
+		},
+		"NoRetryClientError": {
+			reason: "Status codes that are not retryable should be returned at once.",
+			method: http.MethodGet,
+			status: []int{http.StatusNotFound, http.StatusOK},
+			want:   want{attempts: 1, status: http.StatusNotFound},
+		},
+		"NoRetryPost": {
+			reason: "POST requests should not be retried by default.",
+			method: http.MethodPost,
+			status: []int{http.StatusServiceUnavailable, http.StatusOK},
+			want:   want{attempts: 1, status: http.StatusServiceUnavailable},
+		},
+		"RetryPostOptIn": {
+			reason: "POST requests should be retried when the caller opts in.",
+			method: http.MethodPost,
+			opts:   []RequestOption{WithRetryNonIdempotent()},
+			status: []int{http.StatusServiceUnavailable, http.StatusOK},
+			want:   want{attempts: 2, status: http.StatusOK},
+		},
+		"RetryAfterTooLong": {
+			reason: "A Retry-After longer than MaxDelay should not be waited for.",
+			method: http.MethodGet,
+			status: []int{http.StatusTooManyRequests, http.StatusOK},
+			header: http.Header{"Retry-After": []string{"120"}},
+			want:   want{attempts: 1, status: http.StatusTooManyRequests},
+		},
+		"NoRetryTLSError": {
+			reason: "A certificate the client does not trust should not be retried.",
+			method: http.MethodGet,
+			tls:    true,
+			want:   want{attempts: 1},
+		},
+		"Disabled": {
+			reason: "A MaxAttempts of 1 should disable retries.",
+			method: http.MethodGet,
+			opts:   []RequestOption{WithRetryPolicy(RetryPolicy{MaxAttempts: 1})},
+			status: []int{http.StatusServiceUnavailable, http.StatusOK},
+			want:   want{attempts: 1, status: http.StatusServiceUnavailable},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var attempts atomic.Int32
+			srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") == "" {
+					t.Errorf("attempt %d was not signed", attempts.Load()+1)
+				}
+				for k, v := range tc.header {
+					w.Header()[k] = v
+				}
+				w.WriteHeader(tc.status[attempts.Add(1)-1])
+			}))
+			if tc.tls {
+				// The handshake fails before the handler is reached, so
+				// every connection is an attempt.
+				srv.Config.ConnState = func(_ net.Conn, s http.ConnState) {
+					if s == http.StateNew {
+						attempts.Add(1)
+					}
+				}
+				srv.StartTLS()
+			} else {
+				srv.Start()
+			}
+			defer srv.Close()
+
+			cli := NewHttpClient(SignCertificate{AccessKeyID: "ak", SecretAccessKey: "sk"})
+			rsp, err := cli.Do(context.Background(), tc.method, srv.URL, []byte("{}"), tc.opts...)
+			if err != nil && !tc.tls {
+				t.Fatalf("\n%s\nDo(...): %v", tc.reason, err)
+			}
+			if err == nil && tc.tls {
+				t.Fatalf("\n%s\nDo(...): want a certificate error", tc.reason)
+			}
+			got := want{attempts: int(attempts.Load())}
+			if rsp != nil {
+				got.status = rsp.StatusCode
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nDo(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	cases := map[string]struct {
+		value  string
+		want   time.Duration
+		wantOk bool
+	}{
+		"Empty":   {value: "", wantOk: false},
+		"Seconds": {value: "3", want: 3 * time.Second, wantOk: true},
+		"Past":    {value: "Mon, 02 Jan 2006 15:04:05 GMT", want: 0, wantOk: true},
+		"Garbage": {value: "soon", wantOk: false},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, ok := retryAfter(tc.value)
+			if got != tc.want || ok != tc.wantOk {
+				t.Errorf("retryAfter(%q): want %v, %t, got %v, %t", tc.value, tc.want, tc.wantOk, got, ok)
+			}
+		})
+	}
+}
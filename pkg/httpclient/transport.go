@@ -0,0 +1,85 @@
+package httpclient
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// TransportOptions tune the connection pool shared by every HttpClient.
+type TransportOptions struct {
+	// MaxIdleConns caps the idle connections kept across all hosts.
+	MaxIdleConns int
+	// MaxIdleConnsPerHost caps the idle connections kept to a single host.
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost caps all connections to a single host. Zero means no
+	// limit.
+	MaxConnsPerHost int
+	// IdleConnTimeout closes connections that were idle for this long.
+	IdleConnTimeout time.Duration
+	// DialTimeout bounds establishing a TCP connection.
+	DialTimeout time.Duration
+	// TLSHandshakeTimeout bounds the TLS handshake of https endpoints.
+	TLSHandshakeTimeout time.Duration
+	// ResponseHeaderTimeout bounds waiting for response headers once a
+	// request has been written. Zero means no limit.
+	ResponseHeaderTimeout time.Duration
+	// EnableHTTP2 negotiates HTTP/2 with https endpoints that support it.
+	EnableHTTP2 bool
+	// DisableCompression stops requesting gzip encoded responses.
+	DisableCompression bool
+}
+
+// DefaultTransportOptions returns the transport settings used unless
+// SetTransportOptions is called.
+func DefaultTransportOptions() TransportOptions {
+	return TransportOptions{
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 20,
+		IdleConnTimeout:     90 * time.Second,
+		DialTimeout:         30 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+}
+
+// NewTransport returns an http.Transport configured with o.
+func NewTransport(o TransportOptions) *http.Transport {
+	d := &net.Dialer{Timeout: o.DialTimeout, KeepAlive: 30 * time.Second}
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           d.DialContext,
+		MaxIdleConns:          o.MaxIdleConns,
+		MaxIdleConnsPerHost:   o.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       o.MaxConnsPerHost,
+		IdleConnTimeout:       o.IdleConnTimeout,
+		TLSHandshakeTimeout:   o.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: o.ResponseHeaderTimeout,
+		ExpectContinueTimeout: 1 * time.Second,
+		ForceAttemptHTTP2:     o.EnableHTTP2,
+		DisableCompression:    o.DisableCompression,
+	}
+}
+
+var (
+	transportMu sync.RWMutex
+	transport   http.RoundTripper = NewTransport(DefaultTransportOptions())
+)
+
+// SetTransportOptions replaces the transport shared by HttpClients created
+// afterwards. Existing clients keep using the previous transport.
+func SetTransportOptions(o TransportOptions) {
+	t := NewTransport(o)
+	transportMu.Lock()
+	defer transportMu.Unlock()
+	if old, ok := transport.(*http.Transport); ok {
+		old.CloseIdleConnections()
+	}
+	transport = t
+}
+
+func sharedTransport() http.RoundTripper {
+	transportMu.RLock()
+	defer transportMu.RUnlock()
+	return transport
+}
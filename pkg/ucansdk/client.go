@@ -0,0 +1,82 @@
+package ucansdk
+
+import (
+	"context"
+	"strings"
+
+	"github.com/crossplane/provider-ucan/pkg/httpclient"
+)
+
+// Default in-cluster addresses of the UCAN apiservers.
+const (
+	DefaultVirtualMachineURL = "http://zed-virtualmachine-apiserver.ucan-system.svc.cluster.local:8088"
+	DefaultVolumeURL         = "http://zed-volume-apiserver.ucan-system.svc.cluster.local:8088"
+	DefaultNetworkURL        = "http://zed-network-apiserver.ucan-system.svc.cluster.local:8088"
+)
+
+// An Endpoint locates a single UCAN service.
+type Endpoint struct {
+	// BaseURL is the scheme and host of the service, e.g.
+	// http://virtualmachine.ucan.ustack.com.
+	BaseURL string
+	// PathPrefix is prepended to every API path, e.g. /virtualmachine.
+	PathPrefix string
+}
+
+// URL joins the endpoint with the supplied API path.
+func (e Endpoint) URL(path string) string {
+	prefix := strings.Trim(e.PathPrefix, "/")
+	if prefix != "" {
+		prefix = "/" + prefix
+	}
+	return strings.TrimRight(e.BaseURL, "/") + prefix + "/" + strings.TrimLeft(path, "/")
+}
+
+// Endpoints is the catalog of UCAN services used by a Client.
+type Endpoints struct {
+	VirtualMachine Endpoint
+	Volume         Endpoint
+	Network        Endpoint
+}
+
+// DefaultEndpoints returns the in-cluster UCAN service catalog.
+func DefaultEndpoints() Endpoints {
+	return Endpoints{
+		VirtualMachine: Endpoint{BaseURL: DefaultVirtualMachineURL},
+		Volume:         Endpoint{BaseURL: DefaultVolumeURL},
+		Network:        Endpoint{BaseURL: DefaultNetworkURL},
+	}
+}
+
+// A Client talks to the UCAN services of a single installation.
+type Client struct {
+	HttpClient *httpclient.HttpClient
+	Endpoints  Endpoints
+
+	opts []httpclient.RequestOption
+}
+
+// NewClient returns a Client that sends requests through cli to the supplied
+// endpoints.
+func NewClient(cli *httpclient.HttpClient, endpoints Endpoints) *Client {
+	return &Client{HttpClient: cli, Endpoints: endpoints}
+}
+
+// WithOptions returns a copy of the Client that applies opts to every request,
+// e.g. a logger scoped to a single managed resource. The receiver is not
+// modified.
+func (c *Client) WithOptions(opts ...httpclient.RequestOption) *Client {
+	cp := *c
+	cp.opts = append(append([]httpclient.RequestOption{}, c.opts...), opts...)
+	return &cp
+}
+
+// do sends a request and returns the response body. Responses with a status
+// code of 400 or above are returned as an *APIError.
+func (c *Client) do(ctx context.Context, method, url string, data []byte, opts ...httpclient.RequestOption) ([]byte, error) {
+	rsp, err := c.HttpClient.Do(ctx, method, url, data, append(append([]httpclient.RequestOption{}, c.opts...), opts...)...)
+	if err != nil {
+		return nil, err
+	}
+	return checkResponse(rsp)
+}
@@ -0,0 +1,44 @@
+package ucansdk
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEndpointURL(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		endpoint Endpoint
+		path     string
+		want     string
+	}{
+		"NoPrefix": {
+			reason:   "Paths should be appended to the base URL.",
+			endpoint: Endpoint{BaseURL: DefaultVirtualMachineURL},
+			path:     "/v3/servers",
+			want:     DefaultVirtualMachineURL + "/v3/servers",
+		},
+		"Prefix": {
+			reason:   "The path prefix should sit between the base URL and the path.",
+			endpoint: Endpoint{BaseURL: "http://virtualmachine.ucan.ustack.com", PathPrefix: "virtualmachine"},
+			path:     "/v3/servers",
+			want:     "http://virtualmachine.ucan.ustack.com/virtualmachine/v3/servers",
+		},
+		"Slashes": {
+			reason:   "Redundant slashes should be collapsed.",
+			endpoint: Endpoint{BaseURL: "http://network.ucan.ustack.com/", PathPrefix: "/network/"},
+			path:     "v3/floatingips",
+			want:     "http://network.ucan.ustack.com/network/v3/floatingips",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.endpoint.URL(tc.path)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nURL(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
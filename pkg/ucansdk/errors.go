@@ -0,0 +1,155 @@
+package ucansdk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/crossplane/provider-ucan/pkg/httpclient"
+)
+
+// Headers that UCAN services use to return the ID of a request.
+var requestIDHeaders = []string{
+	"X-Request-Id",
+	"X-Openstack-Request-Id",
+	"X-Compute-Request-Id",
+}
+
+// An APIError is returned when a UCAN service answers with a status code of
+// 400 or above.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Code is the service error code, e.g. itemNotFound or OverQuota.
+	Code string
+	// Message is the human readable error returned by the service.
+	Message string
+	// RequestID identifies the request in the service logs.
+	RequestID string
+}
+
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	s := fmt.Sprintf("ucan: %d", e.StatusCode)
+	if e.Code != "" {
+		s += " " + e.Code
+	}
+	s += ": " + msg
+	if e.RequestID != "" {
+		s += " (request-id: " + e.RequestID + ")"
+	}
+	return s
+}
+
+// errorBody matches the flat form of a UCAN error payload, e.g.
+// {"code": "OverQuota", "message": "..."}.
+type errorBody struct {
+	Code    json.RawMessage `json:"code"`
+	Type    string          `json:"type"`
+	Message string          `json:"message"`
+	Detail  string          `json:"detail"`
+}
+
+// newAPIError builds an APIError from a failed response. UCAN services return
+// either a flat error object or one wrapped in a single key naming the error,
+// e.g. {"itemNotFound": {"code": 404, "message": "..."}}.
+func newAPIError(rsp *httpclient.Response) *APIError {
+	e := &APIError{StatusCode: rsp.StatusCode}
+	for _, h := range requestIDHeaders {
+		if id := rsp.Header.Get(h); id != "" {
+			e.RequestID = id
+			break
+		}
+	}
+
+	var flat errorBody
+	if err := json.Unmarshal(rsp.Body, &flat); err == nil && flat.Message != "" {
+		e.Code, e.Message = flat.code(""), flat.message()
+		return e
+	}
+	var wrapped map[string]errorBody
+	if err := json.Unmarshal(rsp.Body, &wrapped); err == nil && len(wrapped) == 1 {
+		for name, b := range wrapped {
+			e.Code, e.Message = b.code(name), b.message()
+		}
+		return e
+	}
+	e.Message = strings.TrimSpace(string(rsp.Body))
+	return e
+}
+
+func (b errorBody) code(name string) string {
+	var s string
+	if err := json.Unmarshal(b.Code, &s); err == nil && s != "" {
+		return s
+	}
+	if b.Type != "" {
+		return b.Type
+	}
+	return name
+}
+
+func (b errorBody) message() string {
+	if b.Detail != "" && b.Detail != b.Message {
+		return b.Message + ": " + b.Detail
+	}
+	return b.Message
+}
+
+// checkResponse returns the body of a successful response, or an APIError.
+func checkResponse(rsp *httpclient.Response) ([]byte, error) {
+	if rsp.StatusCode >= http.StatusBadRequest {
+		return rsp.Body, newAPIError(rsp)
+	}
+	return rsp.Body, nil
+}
+
+// IsNotFound returns true if err is an APIError for a missing resource.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsConflict returns true if err is an APIError caused by a conflicting
+// request, e.g. an action on a resource in the wrong state.
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+// IsQuotaExceeded returns true if err is an APIError caused by an exhausted
+// project quota.
+func IsQuotaExceeded(err error) bool {
+	var e *APIError
+	if !errors.As(err, &e) {
+		return false
+	}
+	if e.StatusCode == http.StatusRequestEntityTooLarge {
+		return true
+	}
+	s := strings.ToLower(e.Code + " " + e.Message)
+	return (e.StatusCode == http.StatusForbidden || e.StatusCode == http.StatusBadRequest) &&
+		(strings.Contains(s, "quota") || strings.Contains(s, "overlimit"))
+}
+
+// IsRetryable returns true if err is an APIError that may succeed when the
+// request is sent again.
+func IsRetryable(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout)
+}
+
+func hasStatus(err error, codes ...int) bool {
+	var e *APIError
+	if !errors.As(err, &e) {
+		return false
+	}
+	for _, c := range codes {
+		if e.StatusCode == c {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package ucansdk
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-ucan/pkg/httpclient"
+)
+
+func TestNewAPIError(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		rsp    *httpclient.Response
+		want   *APIError
+	}{
+		"Wrapped": {
+			reason: "Errors wrapped in a key naming the error should use that key as the code.",
+			rsp: &httpclient.Response{
+				StatusCode: http.StatusNotFound,
+				Header:     http.Header{"X-Openstack-Request-Id": []string{"req-1"}},
+				Body:       []byte(`{"itemNotFound": {"code": 404, "message": "Instance could not be found."}}`),
+			},
+			want: &APIError{StatusCode: http.StatusNotFound, Code: "itemNotFound", Message: "Instance could not be found.", RequestID: "req-1"},
+		},
+		"Flat": {
+			reason: "Flat errors should keep their own code.",
+			rsp: &httpclient.Response{
+				StatusCode: http.StatusForbidden,
+				Header:     http.Header{"X-Request-Id": []string{"req-2"}},
+				Body:       []byte(`{"code": "OverQuota", "message": "Quota exceeded for floatingip."}`),
+			},
+			want: &APIError{StatusCode: http.StatusForbidden, Code: "OverQuota", Message: "Quota exceeded for floatingip.", RequestID: "req-2"},
+		},
+		"Neutron": {
+			reason: "Neutron style errors should use their type as the code.",
+			rsp: &httpclient.Response{
+				StatusCode: http.StatusConflict,
+				Header:     http.Header{},
+				Body:       []byte(`{"NeutronError": {"type": "FloatingIPPortAlreadyAssociated", "message": "Port is in use.", "detail": ""}}`),
+			},
+			want: &APIError{StatusCode: http.StatusConflict, Code: "FloatingIPPortAlreadyAssociated", Message: "Port is in use."},
+		},
+		"PlainText": {
+			reason: "Bodies that are not JSON should be returned as the message.",
+			rsp: &httpclient.Response{
+				StatusCode: http.StatusBadGateway,
+				Header:     http.Header{},
+				Body:       []byte("bad gateway\n"),
+			},
+			want: &APIError{StatusCode: http.StatusBadGateway, Message: "bad gateway"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := newAPIError(tc.rsp)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nnewAPIError(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestErrorHelpers(t *testing.T) {
+	notFound := errors.Wrap(&APIError{StatusCode: http.StatusNotFound}, "cannot get virtual machine")
+	quota := &APIError{StatusCode: http.StatusForbidden, Message: "Quota exceeded for cores"}
+	unavailable := &APIError{StatusCode: http.StatusServiceUnavailable}
+
+	if !IsNotFound(notFound) {
+		t.Errorf("IsNotFound(...): want true for a wrapped 404")
+	}
+	if IsNotFound(errors.New("boom")) {
+		t.Errorf("IsNotFound(...): want false for a non API error")
+	}
+	if !IsQuotaExceeded(quota) {
+		t.Errorf("IsQuotaExceeded(...): want true for a quota 403")
+	}
+	if IsQuotaExceeded(&APIError{StatusCode: http.StatusForbidden, Message: "policy does not allow"}) {
+		t.Errorf("IsQuotaExceeded(...): want false for a policy 403")
+	}
+	if !IsRetryable(unavailable) || IsRetryable(notFound) {
+		t.Errorf("IsRetryable(...): want true only for a 503")
+	}
+	if !IsConflict(&APIError{StatusCode: http.StatusConflict}) {
+		t.Errorf("IsConflict(...): want true for a 409")
+	}
+}
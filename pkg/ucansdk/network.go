@@ -1,33 +1,48 @@
 package ucansdk
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/crossplane/provider-ucan/pkg/httpclient"
 )
 
 type CreateEipReqParam struct {
-	ID              string `json:"id"`
-	Name            string `json:"name"`
-	ProjectID       string `json:"project_id"`
-	FloatingNetwork string `json:"floating_network_id"`
-	CellId          string `json:"cell_id"`
-	// QosPolicyId     string  `json:"qos_policy_id"`
-	RouteId        string  `json:"route_id"`
-	Bandwidth      int     `json:"bandwidth" binding:"required"`
-	Isp            string  `json:"isp" binding:"required"`
-	Description    string  `json:"description"`
-	FloatingIP     *string `json:"floating_ip_address"`
-	FixedIPAddress string  `json:"fixed_ip_address"`
-	UserID         string  `json:"user_id"`
-	ReservationID  string  `json:"reservation_id"`
+	ID              string  `json:"id"`
+	Name            string  `json:"name"`
+	ProjectID       string  `json:"project_id"`
+	FloatingNetwork string  `json:"floating_network_id"`
+	CellId          string  `json:"cell_id"`
+	QosPolicyId     string  `json:"qos_policy_id,omitempty"`
+	RouteId         string  `json:"route_id"`
+	Bandwidth       int     `json:"bandwidth" binding:"required"`
+	Isp             string  `json:"isp" binding:"required"`
+	Description     string  `json:"description"`
+	FloatingIP      *string `json:"floating_ip_address"`
+	FixedIPAddress  string  `json:"fixed_ip_address"`
+	UserID          string  `json:"user_id"`
+	ReservationID   string  `json:"reservation_id"`
 }
 
 type CreateEipReq struct {
 	FloatingIp CreateEipReqParam `json:"floatingip"`
 }
 
+type UpdateEipReq struct {
+	FloatingIp UpdateEipReqParam `json:"floatingip"`
+}
+
+// UpdateEipReqParam holds the fields of a floating IP to update. Description
+// is a pointer so that it can be cleared.
+type UpdateEipReqParam struct {
+	Name        string  `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	QosPolicyId string  `json:"qos_policy_id,omitempty"`
+	Bandwidth   int     `json:"bandwidth,omitempty"`
+}
+
 type EipGetResponse struct {
 	FloatingIps EipResp `json:"floatingips"`
 }
@@ -51,24 +66,22 @@ type EipResp struct {
 	Updated         time.Time `json:"updated_at"`
 }
 
-var eipHost = "http://zed-network-apiserver.ucan-system.svc.cluster.local:8088"
-
-// var eipHost = "http://volume.ucan.ustack.com"
+func GetEip(ctx context.Context, client *Client, projectId, eipId string) ([]byte, error) {
+	url := client.Endpoints.Network.URL(fmt.Sprintf("/v3/floatingips/%s", eipId))
+	return client.do(ctx, http.MethodGet, url, nil, httpclient.WithNamespace(projectId))
+}
 
-func GetEip(client *httpclient.HttpClient, eipId string) ([]byte, int, error) {
-	url := fmt.Sprintf("%s/v3/floatingips/%s", eipHost, eipId)
-	// url := fmt.Sprintf("%s/network/v3/floatingips/%s", eipHost, eipId)
-	return client.GET(url, nil)
+func DelEip(ctx context.Context, client *Client, projectId, eipId string) ([]byte, error) {
+	url := client.Endpoints.Network.URL(fmt.Sprintf("/v3/floatingips/%s", eipId))
+	return client.do(ctx, http.MethodDelete, url, nil, httpclient.WithNamespace(projectId))
 }
 
-func DelEip(client *httpclient.HttpClient, eipId string) ([]byte, int, error) {
-	url := fmt.Sprintf("%s/v3/floatingips/%s", eipHost, eipId)
-	// url := fmt.Sprintf("%s/network/v3/floatingips/%s", eipHost, eipId)
-	return client.DELETE(url, nil)
+func CreateEip(ctx context.Context, client *Client, req []byte, projectId string) ([]byte, error) {
+	url := client.Endpoints.Network.URL("/v3/floatingips")
+	return client.do(ctx, http.MethodPost, url, req, httpclient.WithNamespace(projectId))
 }
 
-func CreateEip(client *httpclient.HttpClient, req []byte) ([]byte, int, error) {
-	url := fmt.Sprintf("%s/v3/floatingips", eipHost)
-	// url := fmt.Sprintf("%s/network/v3/floatingips", eipHost)
-	return client.POST(url, req)
+func UpdateEip(ctx context.Context, client *Client, projectId, eipId string, req []byte) ([]byte, error) {
+	url := client.Endpoints.Network.URL(fmt.Sprintf("/v3/floatingips/%s", eipId))
+	return client.do(ctx, http.MethodPut, url, req, httpclient.WithNamespace(projectId))
 }
@@ -1,7 +1,11 @@
 package ucansdk
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/crossplane/provider-ucan/pkg/httpclient"
@@ -9,6 +13,7 @@ import (
 
 type CreateServerReq struct {
 	Name               string               `json:"name" binding:"required"`
+	Description        string               `json:"description,omitempty"`
 	ProjectID          string               `json:"project_id" binding:"required"`
 	CellID             string               `json:"cell_id"`
 	ReservationID      string               `json:"reservation_id"`
@@ -24,6 +29,54 @@ type CreateServerReq struct {
 	BlockDeviceMapping []BlockDeviceMapping `json:"block_device_mapping"`
 }
 
+type UpdateServerReq struct {
+	Server UpdateServerParams `json:"server"`
+}
+
+type UpdateServerParams struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type ServerMetadataReq struct {
+	Metadata map[string]string `json:"metadata"`
+}
+
+// Statuses of a server.
+const (
+	ServerStatusRunning      = "Running"
+	ServerStatusActive       = "ACTIVE"
+	ServerStatusShutoff      = "SHUTOFF"
+	ServerStatusPaused       = "PAUSED"
+	ServerStatusSuspended    = "SUSPENDED"
+	ServerStatusResize       = "RESIZE"
+	ServerStatusVerifyResize = "VERIFY_RESIZE"
+	ServerStatusError        = "ERROR"
+)
+
+// Server actions, see VmAction.
+const (
+	ActionResize        = "resize"
+	ActionConfirmResize = "confirmResize"
+	ActionRevertResize  = "revertResize"
+	ActionStart         = "os-start"
+	ActionStop          = "os-stop"
+	ActionPause         = "pause"
+	ActionUnpause       = "unpause"
+	ActionSuspend       = "suspend"
+	ActionResume        = "resume"
+)
+
+type ResizeParams struct {
+	FlavorRef string `json:"flavorRef"`
+}
+
+// NewServerAction returns the body of a server action. params is nil for
+// actions that take no parameters.
+func NewServerAction(action string, params any) ([]byte, error) {
+	return json.Marshal(map[string]any{action: params})
+}
+
 type BlockDeviceMapping struct {
 	BootIndex           int    `json:"boot_index,omitempty" binding:"omitempty"`
 	DeleteOnTermination bool   `json:"delete_on_termination,omitempty"`
@@ -78,6 +131,9 @@ type ServerResp struct {
 		Updated         time.Time            `json:"updated"`
 		UserID          string               `json:"user_id"`
 		VolumesAttached []VolumeAttached     `json:"os-extended-volumes:volumes_attached"`
+
+		// AdminPass is only returned when the server is created.
+		AdminPass string `json:"adminPass,omitempty"`
 	} `json:"server"`
 }
 
@@ -99,6 +155,18 @@ type Image struct {
 	Properties ImageProperties `json:"properties"`
 }
 
+// UnmarshalJSON decodes an image object, or the empty string UCAN returns for
+// the image of a server booted from a volume.
+func (i *Image) UnmarshalJSON(data []byte) error {
+	var id string
+	if err := json.Unmarshal(data, &id); err == nil {
+		*i = Image{ID: id}
+		return nil
+	}
+	type image Image
+	return json.Unmarshal(data, (*image)(i))
+}
+
 type ImageProperties struct {
 	Architecture    string `json:"architecture"`
 	AutoDiskConfig  string `json:"auto_disk_config"`
@@ -137,24 +205,76 @@ type FlavorResponse struct {
 	OSFlavorAccessIsPublic   bool `json:"os-flavor-access:is_public"`
 }
 
-var vmHost = "http://zed-virtualmachine-apiserver.ucan-system.svc.cluster.local:8088"
+func GetVm(ctx context.Context, client *Client, vmId string) ([]byte, error) {
+	url := client.Endpoints.VirtualMachine.URL(fmt.Sprintf("/v3/servers/%s", vmId))
+	return client.do(ctx, http.MethodGet, url, nil)
+}
 
-// var vmHost = "http://virtualmachine.ucan.ustack.com"
+func DelVm(ctx context.Context, client *Client, vmId string) ([]byte, error) {
+	url := client.Endpoints.VirtualMachine.URL(fmt.Sprintf("/v3/servers/%s", vmId))
+	return client.do(ctx, http.MethodDelete, url, nil)
+}
+
+func CreateVm(ctx context.Context, client *Client, req []byte) ([]byte, error) {
+	url := client.Endpoints.VirtualMachine.URL("/v3/servers")
+	return client.do(ctx, http.MethodPost, url, req)
+}
+
+func UpdateVm(ctx context.Context, client *Client, vmId string, req []byte) ([]byte, error) {
+	url := client.Endpoints.VirtualMachine.URL(fmt.Sprintf("/v3/servers/%s", vmId))
+	return client.do(ctx, http.MethodPut, url, req)
+}
+
+// UpdateVmMetadata merges the supplied metadata items into the metadata of the
+// server. Items that are not supplied are left unchanged, so the request is
+// safe to retry.
+func UpdateVmMetadata(ctx context.Context, client *Client, vmId string, req []byte) ([]byte, error) {
+	url := client.Endpoints.VirtualMachine.URL(fmt.Sprintf("/v3/servers/%s/metadata", vmId))
+	return client.do(ctx, http.MethodPost, url, req, httpclient.WithRetryNonIdempotent())
+}
+
+// DeleteVmMetadataItem deletes a metadata item of the server.
+func DeleteVmMetadataItem(ctx context.Context, client *Client, vmId, key string) ([]byte, error) {
+	path := fmt.Sprintf("/v3/servers/%s/metadata/%s", vmId, url.PathEscape(key))
+	return client.do(ctx, http.MethodDelete, client.Endpoints.VirtualMachine.URL(path), nil)
+}
+
+func VmAction(ctx context.Context, client *Client, vmId string, req []byte) ([]byte, error) {
+	url := client.Endpoints.VirtualMachine.URL(fmt.Sprintf("/v3/servers/%s/action", vmId))
+	return client.do(ctx, http.MethodPost, url, req)
+}
+
+type AttachVolumeReq struct {
+	VolumeAttachment AttachVolumeParams `json:"volumeAttachment"`
+}
+
+type AttachVolumeParams struct {
+	VolumeID string `json:"volumeId"`
+	Device   string `json:"device,omitempty"`
+}
+
+type VolumeAttachmentResp struct {
+	VolumeAttachment struct {
+		ID       string `json:"id"`
+		ServerID string `json:"serverId"`
+		VolumeID string `json:"volumeId"`
+		Device   string `json:"device"`
+	} `json:"volumeAttachment"`
+}
 
-func GetVm(client *httpclient.HttpClient, vmId string) ([]byte, int, error) {
-	// url := fmt.Sprintf("%s/virtualmachine/v3/servers/%s", vmHost, vmId)
-	url := fmt.Sprintf("%s/v3/servers/%s", vmHost, vmId)
-	return client.GET(url, nil)
+func AttachVolume(ctx context.Context, client *Client, vmId string, req []byte) ([]byte, error) {
+	url := client.Endpoints.VirtualMachine.URL(fmt.Sprintf("/v3/servers/%s/os-volume_attachments", vmId))
+	return client.do(ctx, http.MethodPost, url, req)
 }
 
-func DelVm(client *httpclient.HttpClient, vmId string) ([]byte, int, error) {
-	// url := fmt.Sprintf("%s/virtualmachine/v3/servers/%s", vmHost, vmId)
-	url := fmt.Sprintf("%s/v3/servers/%s", vmHost, vmId)
-	return client.DELETE(url, nil)
+func GetVolumeAttachment(ctx context.Context, client *Client, vmId, volumeId string) ([]byte, error) {
+	url := client.Endpoints.VirtualMachine.URL(fmt.Sprintf("/v3/servers/%s/os-volume_attachments/%s", vmId, volumeId))
+	return client.do(ctx, http.MethodGet, url, nil)
 }
 
-func CreateVm(client *httpclient.HttpClient, req []byte) ([]byte, int, error) {
-	// url := fmt.Sprintf("%s/virtualmachine/v3/servers", vmHost)
-	url := fmt.Sprintf("%s/v3/servers", vmHost)
-	return client.POST(url, req)
+// DetachVolume requests the detachment of a volume from a server. The volume
+// is detached asynchronously.
+func DetachVolume(ctx context.Context, client *Client, vmId, volumeId string) ([]byte, error) {
+	url := client.Endpoints.VirtualMachine.URL(fmt.Sprintf("/v3/servers/%s/os-volume_attachments/%s", vmId, volumeId))
+	return client.do(ctx, http.MethodDelete, url, nil)
 }
@@ -1,7 +1,9 @@
 package ucansdk
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/crossplane/provider-ucan/pkg/httpclient"
@@ -24,6 +26,48 @@ type VolumeSpec struct {
 	CellID             string         `json:"cell_id"`
 }
 
+// Statuses of a volume.
+const (
+	VolumeStatusAvailable      = "available"
+	VolumeStatusInUse          = "in-use"
+	VolumeStatusExtending      = "extending"
+	VolumeStatusErrorExtending = "error_extending"
+	VolumeStatusRetyping       = "retyping"
+)
+
+// Migration statuses of a volume.
+const (
+	MigrationStatusMigrating  = "migrating"
+	MigrationStatusCompleting = "completing"
+	MigrationStatusError      = "error"
+)
+
+type ExtendVolumeReq struct {
+	Extend ExtendVolumeParams `json:"os-extend"`
+}
+
+type ExtendVolumeParams struct {
+	NewSize int `json:"new_size"`
+}
+
+type RetypeVolumeReq struct {
+	Retype RetypeVolumeParams `json:"os-retype"`
+}
+
+type RetypeVolumeParams struct {
+	NewType         string `json:"new_type"`
+	MigrationPolicy string `json:"migration_policy,omitempty"`
+}
+
+type UpdateVolumeReq struct {
+	Volume UpdateVolumeParams `json:"volume"`
+}
+
+type UpdateVolumeParams struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
 type VolumeSchedulerHints struct {
 	SameHost []string `json:"same_host"`
 }
@@ -53,6 +97,8 @@ type VolumeResp struct {
 		Metadata         map[string]any `json:"metadata"`
 		Links            []Link         `json:"links"`
 
+		Attachments []VolumeAttachment `json:"attachments"`
+
 		ConsistencyGroupID *string `json:"consistency_group_id,omitempty"`
 		MigrationStatus    *string `json:"migration_status,omitempty"`
 		ReplicationStatus  *string `json:"replication_status,omitempty"`
@@ -68,24 +114,40 @@ type VolumeResp struct {
 	} `json:"volume"`
 }
 
-var volumeHost = "http://zed-volume-apiserver.ucan-system.svc.cluster.local:8088"
+// A VolumeAttachment is an attachment of a volume to a server.
+type VolumeAttachment struct {
+	ID           string     `json:"id"`
+	AttachmentID string     `json:"attachment_id"`
+	VolumeID     string     `json:"volume_id"`
+	ServerID     string     `json:"server_id"`
+	HostName     *string    `json:"host_name"`
+	Device       string     `json:"device"`
+	AttachedAt   *time.Time `json:"attached_at,omitempty"`
+}
 
-// var volumeHost = "http://volume.ucan.ustack.com"
+func GetVolume(ctx context.Context, client *Client, projectId, volumeId string) ([]byte, error) {
+	url := client.Endpoints.Volume.URL(fmt.Sprintf("/v3/%s/volumes/%s", projectId, volumeId))
+	return client.do(ctx, http.MethodGet, url, nil)
+}
+
+func DelVolume(ctx context.Context, client *Client, projectId, volumeId string) ([]byte, error) {
+	url := client.Endpoints.Volume.URL(fmt.Sprintf("/v3/%s/volumes/%s", projectId, volumeId))
+	return client.do(ctx, http.MethodDelete, url, nil)
+}
 
-func GetVolume(client *httpclient.HttpClient, projectId, volumeId string) ([]byte, int, error) {
-	url := fmt.Sprintf("%s/v3/%s/volumes/%s", volumeHost, projectId, volumeId)
-	// url := fmt.Sprintf("%s/volume/v3/%s/volumes/%s", volumeHost, projectId, volumeId)
-	return client.GET(url, nil)
+func CreateVolume(ctx context.Context, client *Client, req []byte, projectId string) ([]byte, error) {
+	url := client.Endpoints.Volume.URL(fmt.Sprintf("/v3/%s/volumes", projectId))
+	return client.do(ctx, http.MethodPost, url, req)
 }
 
-func DelVolume(client *httpclient.HttpClient, projectId, volumeId string) ([]byte, int, error) {
-	url := fmt.Sprintf("%s/v3/%s/volumes/%s", volumeHost, projectId, volumeId)
-	// url := fmt.Sprintf("%s/volume/v3/%s/volumes/%s", volumeHost, projectId, volumeId)
-	return client.DELETE(url, nil)
+func UpdateVolume(ctx context.Context, client *Client, projectId, volumeId string, req []byte) ([]byte, error) {
+	url := client.Endpoints.Volume.URL(fmt.Sprintf("/v3/%s/volumes/%s", projectId, volumeId))
+	return client.do(ctx, http.MethodPut, url, req)
 }
 
-func CreateVolume(client *httpclient.HttpClient, req []byte, projectId string) ([]byte, int, error) {
-	url := fmt.Sprintf("%s/v3/%s/volumes", volumeHost, projectId)
-	// url := fmt.Sprintf("%s/volume/v3/%s/volumes", volumeHost, projectId)
-	return client.POST(url, req)
+// VolumeAction requests an action on a volume, e.g. os-extend or os-retype.
+// It uses microversion 3.42, which allows in-use volumes to be extended.
+func VolumeAction(ctx context.Context, client *Client, projectId, volumeId string, req []byte) ([]byte, error) {
+	url := client.Endpoints.Volume.URL(fmt.Sprintf("/v3/%s/volumes/%s/action", projectId, volumeId))
+	return client.do(ctx, http.MethodPost, url, req, httpclient.WithHeader("OpenStack-API-Version", "volume 3.42"))
 }